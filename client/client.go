@@ -0,0 +1,225 @@
+// Package client is a Go client for Hand's HTTP API and party event
+// stream.
+//
+// Player endpoints authenticate with the player's JWT (Token); internal
+// endpoints with a tenant's service credential (ServiceToken). A game
+// server acting for many players can share one Client and derive a
+// per-player copy with WithToken:
+//
+//	hand := client.New("http://hand:8003")
+//	party, err := hand.WithToken(jwt).JoinParty(ctx, code)
+//	if errors.Is(err, client.ErrPartyFull) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls retries. Calls that are safe to repeat are retried
+// after network errors and 5xx responses; any call is retried after a 429,
+// as the server rejected it without acting on it.
+type RetryPolicy struct {
+	// MaxAttempts counts the first try; 1 disables retries.
+	MaxAttempts int
+	// MinBackoff doubles after each attempt, with jitter, up to MaxBackoff.
+	// A 429's Retry-After takes precedence.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+// Client calls one Hand deployment. Its fields may be changed before first
+// use; WithToken and WithGame return modified copies.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Token is a player JWT, sent to player endpoints and the event stream.
+	Token string
+	// ServiceToken is a service credential, sent to internal endpoints.
+	ServiceToken string
+	// Game selects the game namespace for player endpoints when the token
+	// doesn't name one.
+	Game string
+
+	// Retry defaults to DefaultRetryPolicy when zero.
+	Retry RetryPolicy
+}
+
+const defaultTimeout = 10 * time.Second
+
+// apiVersion prefixes every API path.
+const apiVersion = "/v1"
+
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// WithToken returns a copy of c that acts as the player token belongs to.
+func (c *Client) WithToken(token string) *Client {
+	cp := *c
+	cp.Token = token
+	return &cp
+}
+
+// WithGame returns a copy of c that uses the given game namespace.
+func (c *Client) WithGame(game string) *Client {
+	cp := *c
+	cp.Game = game
+	return &cp
+}
+
+// call describes one request.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	// service sends the service credential instead of the player token.
+	service bool
+	// idempotent calls may be repeated after a failure the server may
+	// already have acted on.
+	idempotent bool
+}
+
+// do sends the call, retrying per the policy, and decodes a 2xx response
+// into out when out is non-nil.
+func (c *Client) do(ctx context.Context, cl call, out any) error {
+	var body []byte
+	if cl.body != nil {
+		var err error
+		if body, err = json.Marshal(cl.body); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	policy := c.Retry
+	if policy == (RetryPolicy{}) {
+		policy = DefaultRetryPolicy
+	}
+	backoff := policy.MinBackoff
+
+	for attempt := 1; ; attempt++ {
+		retry, err := c.send(ctx, cl, body, out)
+		if err == nil || !retry || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		// Jitter spreads out clients that failed together.
+		wait := backoff/2 + rand.N(backoff/2+1)
+		retry = cl.idempotent
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			switch {
+			case apiErr.Status == http.StatusTooManyRequests:
+				retry = true
+				if apiErr.RetryAfter > 0 {
+					wait = apiErr.RetryAfter
+				}
+			case apiErr.Status < http.StatusInternalServerError || apiErr.Status == http.StatusNotImplemented:
+				retry = false
+			}
+		}
+		if !retry {
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(backoff*2, policy.MaxBackoff)
+	}
+}
+
+// send makes one attempt. It reports whether the error is one that a retry
+// could fix: an error response or a network failure, but not a bad request
+// or an undecodable success.
+func (c *Client) send(ctx context.Context, cl call, body []byte, out any) (bool, error) {
+	u := c.BaseURL + apiVersion + cl.path
+	if len(cl.query) > 0 {
+		u += "?" + cl.query.Encode()
+	}
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, u, rd)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if cl.service {
+		req.Header.Set("X-Service-Token", c.ServiceToken)
+	} else {
+		c.playerHeaders(req.Header)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return true, responseError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode %s %s response: %w", cl.method, cl.path, err)
+	}
+	return false, nil
+}
+
+func (c *Client) playerHeaders(h http.Header) {
+	if c.Token != "" {
+		h.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Game != "" {
+		h.Set("X-Game", c.Game)
+	}
+}
+
+// responseError builds an *Error from a failed response.
+func responseError(resp *http.Response) *Error {
+	e := &Error{Status: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := json.Unmarshal(data, e); err != nil || e.Code == "" {
+		e.Message = strings.TrimSpace(string(data))
+		if e.Message == "" {
+			e.Message = resp.Status
+		}
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return e
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Error is an error response from Hand. Code is the machine-readable
+// ErrorResponse.Error, such as "party_full"; compare against the Err
+// values with errors.Is:
+//
+//	if errors.Is(err, client.ErrNotInParty) { ... }
+//
+// Authentication failures (401) carry a description rather than a code and
+// match ErrUnauthorized.
+type Error struct {
+	Status  int    `json:"-"`
+	Code    string `json:"error"`
+	Message string `json:"message"`
+	// RetryAfter is set on rate limited (429) responses.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("hand: %d %s", e.Status, e.Code)
+	}
+	return fmt.Sprintf("hand: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Is matches an Err value by code, or by status for values without one.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Code == "" {
+		return t.Status == e.Status
+	}
+	return t.Code == e.Code && e.Status != http.StatusUnauthorized
+}
+
+func code(c string) *Error { return &Error{Code: c} }
+
+var (
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized}
+
+	// Requests
+	ErrInvalidRequest = code("invalid_request")
+	ErrInvalidID      = code("invalid_id")
+	ErrInvalidGame    = code("invalid_game")
+	ErrGameMismatch   = code("game_mismatch")
+	ErrRateLimited    = code("rate_limited")
+
+	// Membership
+	ErrNotInParty     = code("not_in_party")
+	ErrAlreadyInParty = code("already_in_party")
+	ErrInvalidCode    = code("invalid_code")
+	ErrPartyFull      = code("party_full")
+	ErrSpectatorsFull = code("spectators_full")
+	ErrBanned         = code("banned")
+	ErrBlocked        = code("blocked")
+	ErrNotFound       = code("not_found")
+
+	// Permissions and roles
+	ErrNotOwner          = code("not_owner")
+	ErrNotPermitted      = code("not_permitted")
+	ErrInvalidRole       = code("invalid_role")
+	ErrInvalidPermission = code("invalid_permission")
+
+	// Locks
+	ErrPartyLocked   = code("party_locked")
+	ErrAlreadyLocked = code("already_locked")
+	ErrPartyArchived = code("party_archived")
+
+	// Bans and blocks
+	ErrNotBanned     = code("not_banned")
+	ErrNotBlocked    = code("not_blocked")
+	ErrTooManyBlocks = code("too_many_blocks")
+
+	// Merges and splits
+	ErrNoMergeProposal  = code("no_merge_proposal")
+	ErrInvalidPartition = code("invalid_partition")
+	ErrRosterChanged    = code("roster_changed")
+
+	// Sub-groups
+	ErrGroupNotFound = code("group_not_found")
+	ErrGroupFull     = code("group_full")
+	ErrGroupExists   = code("group_exists")
+	ErrTooManyGroups = code("too_many_groups")
+	ErrIsSpectator   = code("is_spectator")
+
+	// Spectators
+	ErrInvalidType = code("invalid_type")
+
+	// Direct invites
+	ErrNoInvite = code("no_invite")
+
+	// Profile, metadata and chat
+	ErrInvalidName        = code("invalid_name")
+	ErrInvalidDescription = code("invalid_description")
+	ErrInvalidEmblem      = code("invalid_emblem")
+	ErrNameRejected       = code("name_rejected")
+	ErrInvalidMetadata    = code("invalid_metadata")
+	ErrInvalidMode        = code("invalid_mode")
+	ErrMetadataTooLarge   = code("metadata_too_large")
+	ErrInvalidMessage     = code("invalid_message")
+	ErrInvalidCursor      = code("invalid_cursor")
+
+	// Service credentials
+	ErrInsufficientScope = code("insufficient_scope")
+
+	// Event stream
+	ErrGatewayDisabled    = code("gateway_disabled")
+	ErrTooManyConnections = code("too_many_connections")
+)
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Internal endpoints. Each authenticates with c.ServiceToken; game selects
+// the namespace, with "" meaning any game for party lookups and the
+// default game for player lookups.
+
+func (c *Client) GetParty(ctx context.Context, partyID uuid.UUID, game string) (*Party, error) {
+	var party Party
+	cl := call{method: http.MethodGet, path: "/internal/parties/" + partyID.String(), query: gameQuery(game), service: true, idempotent: true}
+	if err := c.do(ctx, cl, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// GetPlayerParty returns the player's party, or ErrNotInParty.
+func (c *Client) GetPlayerParty(ctx context.Context, accountID uuid.UUID, game string) (*Party, error) {
+	var party Party
+	cl := call{method: http.MethodGet, path: "/internal/parties/player/" + accountID.String(), query: gameQuery(game), service: true, idempotent: true}
+	if err := c.do(ctx, cl, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// BatchGetParties looks up to 100 parties at once. Parties that don't
+// exist are left out of the result.
+func (c *Client) BatchGetParties(ctx context.Context, partyIDs []uuid.UUID, game string) (map[uuid.UUID]*Party, error) {
+	body := map[string]any{"party_ids": partyIDs, "game": game}
+	return c.batch(ctx, "/internal/parties/batch", body)
+}
+
+// BatchGetPlayerParties looks up the parties of up to 100 players, keyed
+// by account. Players not in a party are left out of the result.
+func (c *Client) BatchGetPlayerParties(ctx context.Context, accountIDs []uuid.UUID, game string) (map[uuid.UUID]*Party, error) {
+	body := map[string]any{"account_ids": accountIDs, "game": game}
+	return c.batch(ctx, "/internal/parties/player/batch", body)
+}
+
+// LockParty locks the party's roster, or renews the caller's lock. A zero
+// ttl uses the server's default of 10 minutes.
+func (c *Client) LockParty(ctx context.Context, partyID uuid.UUID, ttl time.Duration) (*Party, error) {
+	var body any
+	if ttl > 0 {
+		body = map[string]string{"ttl": ttl.String()}
+	}
+	var party Party
+	cl := call{method: http.MethodPost, path: "/internal/parties/" + partyID.String() + "/lock", body: body, service: true, idempotent: true}
+	if err := c.do(ctx, cl, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) UnlockParty(ctx context.Context, partyID uuid.UUID) (*Party, error) {
+	var party Party
+	cl := call{method: http.MethodDelete, path: "/internal/parties/" + partyID.String() + "/lock", service: true, idempotent: true}
+	if err := c.do(ctx, cl, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// SplitPartyByID divides a party into one new party per group, which the
+// holder of its lock may do while it is locked.
+func (c *Client) SplitPartyByID(ctx context.Context, partyID uuid.UUID, groups []SplitGroup) ([]*Party, error) {
+	cl := call{method: http.MethodPost, path: "/internal/parties/" + partyID.String() + "/split", body: map[string]any{"groups": groups}, service: true}
+	return c.split(ctx, cl)
+}
+
+// batch runs a read-only lookup, so it is safe to retry.
+func (c *Client) batch(ctx context.Context, path string, body any) (map[uuid.UUID]*Party, error) {
+	var out struct {
+		Parties map[uuid.UUID]*Party `json:"parties"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: path, body: body, service: true, idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.Parties, nil
+}
+
+func gameQuery(game string) url.Values {
+	if game == "" {
+		return nil
+	}
+	return url.Values{"game": {game}}
+}
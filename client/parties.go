@@ -0,0 +1,395 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Player endpoints. Each acts as the player c.Token belongs to.
+
+func (c *Client) CreateParty(ctx context.Context) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties"}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// MyParty returns the player's party, or ErrNotInParty.
+func (c *Client) MyParty(ctx context.Context) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/mine", idempotent: true}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) JoinParty(ctx context.Context, inviteCode string) (*Party, error) {
+	return c.join(ctx, inviteCode, MemberPlayer)
+}
+
+// JoinPartyAsSpectator joins in one of the party's spectator slots.
+func (c *Client) JoinPartyAsSpectator(ctx context.Context, inviteCode string) (*Party, error) {
+	return c.join(ctx, inviteCode, MemberSpectator)
+}
+
+func (c *Client) join(ctx context.Context, inviteCode, memberType string) (*Party, error) {
+	var party Party
+	body := map[string]string{"invite_code": inviteCode, "type": memberType}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/join", body: body}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// LeaveParty leaves the player's party; if they own it, it is disbanded.
+func (c *Client) LeaveParty(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/leave"}, nil)
+}
+
+// DisbandParty disbands the player's party.
+func (c *Client) DisbandParty(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties"}, nil)
+}
+
+func (c *Client) Kick(ctx context.Context, k Kick) error {
+	body := map[string]any{"account_id": k.AccountID, "ban": k.Ban}
+	if k.Ban && k.BanDuration > 0 {
+		body["ban_duration"] = k.BanDuration.String()
+	}
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/kick", body: body}, nil)
+}
+
+func (c *Client) Bans(ctx context.Context) ([]Ban, error) {
+	var out struct {
+		Bans []Ban `json:"bans"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/bans", idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.Bans, nil
+}
+
+func (c *Client) Unban(ctx context.Context, accountID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/bans/" + accountID.String(), idempotent: true}, nil)
+}
+
+// TransferOwnership makes accountID the owner. The returned party is nil
+// if the transfer succeeded but the party couldn't be re-read.
+func (c *Client) TransferOwnership(ctx context.Context, accountID uuid.UUID) (*Party, error) {
+	return c.updateParty(ctx, call{method: http.MethodPost, path: "/parties/transfer", body: accountBody(accountID)})
+}
+
+// Promote makes a member a moderator. The returned party is nil if the
+// change succeeded but the party couldn't be re-read.
+func (c *Client) Promote(ctx context.Context, accountID uuid.UUID) (*Party, error) {
+	return c.updateParty(ctx, call{method: http.MethodPost, path: "/parties/promote", body: accountBody(accountID)})
+}
+
+// Demote makes a moderator a member. The returned party is nil if the
+// change succeeded but the party couldn't be re-read.
+func (c *Client) Demote(ctx context.Context, accountID uuid.UUID) (*Party, error) {
+	return c.updateParty(ctx, call{method: http.MethodPost, path: "/parties/demote", body: accountBody(accountID)})
+}
+
+// Settings returns the party's effective permission policy.
+func (c *Client) Settings(ctx context.Context) (PermissionPolicy, error) {
+	return c.settings(ctx, call{method: http.MethodGet, path: "/parties/settings", idempotent: true})
+}
+
+// UpdateSettings overrides the listed actions and returns the effective
+// policy.
+func (c *Client) UpdateSettings(ctx context.Context, policy PermissionPolicy) (PermissionPolicy, error) {
+	body := map[string]any{"permissions": policy}
+	return c.settings(ctx, call{method: http.MethodPatch, path: "/parties/settings", body: body, idempotent: true})
+}
+
+func (c *Client) UpdateProfile(ctx context.Context, update ProfileUpdate) (*Profile, error) {
+	var profile Profile
+	if err := c.do(ctx, call{method: http.MethodPatch, path: "/parties/profile", body: update, idempotent: true}, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateMetadata merges keys into the party's metadata; a nil value
+// deletes a key. It returns the merged metadata.
+func (c *Client) UpdateMetadata(ctx context.Context, m Metadata) (Metadata, error) {
+	return c.metadata(ctx, "/parties/metadata", m)
+}
+
+// UpdateMemberMetadata merges keys into the player's own member metadata.
+func (c *Client) UpdateMemberMetadata(ctx context.Context, m Metadata) (Metadata, error) {
+	return c.metadata(ctx, "/parties/metadata/me", m)
+}
+
+// RegenerateInvite replaces the party's invite code and returns the new one.
+func (c *Client) RegenerateInvite(ctx context.Context) (string, error) {
+	var out struct {
+		InviteCode string `json:"invite_code"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/invite"}, &out); err != nil {
+		return "", err
+	}
+	return out.InviteCode, nil
+}
+
+// Invite invites a player into the party, holding a player slot for them
+// until the invite expires. Inviting them again renews it.
+func (c *Client) Invite(ctx context.Context, accountID uuid.UUID) (*Invite, error) {
+	var invite Invite
+	body := map[string]uuid.UUID{"account_id": accountID}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/invites", body: body}, &invite); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (c *Client) Invites(ctx context.Context) (*Invites, error) {
+	var out Invites
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/invites", idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeInvite withdraws the party's invite to the player, freeing its slot.
+func (c *Client) RevokeInvite(ctx context.Context, accountID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/invites/" + accountID.String()}, nil)
+}
+
+// AcceptInvite joins the party that sent the invite, as a player.
+func (c *Client) AcceptInvite(ctx context.Context, partyID uuid.UUID) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/invites/" + partyID.String() + "/accept"}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) DeclineInvite(ctx context.Context, partyID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/invites/" + partyID.String() + "/decline"}, nil)
+}
+
+// ProposeMerge offers to move the player's whole party into the party
+// with the given invite code, replacing any earlier proposal.
+func (c *Client) ProposeMerge(ctx context.Context, inviteCode string) (*MergeProposal, error) {
+	var proposal MergeProposal
+	body := map[string]string{"invite_code": inviteCode}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/merge", body: body}, &proposal); err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (c *Client) MergeProposals(ctx context.Context) (*MergeProposals, error) {
+	var out MergeProposals
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/merge", idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelMerge withdraws the party's proposal.
+func (c *Client) CancelMerge(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/merge"}, nil)
+}
+
+// AcceptMerge moves the members of the party that proposed the merge into
+// the player's party and returns it.
+func (c *Client) AcceptMerge(ctx context.Context, partyID uuid.UUID) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/merge/" + partyID.String() + "/accept"}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) DeclineMerge(ctx context.Context, partyID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/merge/" + partyID.String() + "/decline"}, nil)
+}
+
+// SplitParty divides the player's party into one new party per group and
+// returns them. The groups must cover every member exactly once.
+func (c *Client) SplitParty(ctx context.Context, groups []SplitGroup) ([]*Party, error) {
+	return c.split(ctx, call{method: http.MethodPost, path: "/parties/split", body: map[string]any{"groups": groups}})
+}
+
+// CreateGroup adds a sub-group holding up to maxSize members.
+func (c *Client) CreateGroup(ctx context.Context, name string, maxSize int) (*Group, error) {
+	var group Group
+	body := map[string]any{"name": name, "max_size": maxSize}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/groups", body: body}, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// DeleteGroup removes a sub-group; its members stay in the party.
+func (c *Client) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/groups/" + groupID.String(), idempotent: true}, nil)
+}
+
+func (c *Client) JoinGroup(ctx context.Context, groupID uuid.UUID) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/groups/" + groupID.String() + "/join", idempotent: true}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) LeaveGroup(ctx context.Context) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/groups/leave", idempotent: true}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// AssignGroup puts a member into a sub-group, or takes them out of theirs
+// when groupID is nil.
+func (c *Client) AssignGroup(ctx context.Context, accountID uuid.UUID, groupID *uuid.UUID) (*Party, error) {
+	var party Party
+	body := map[string]any{"account_id": accountID, "group_id": groupID}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/groups/assign", body: body, idempotent: true}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// Messages returns a page of chat history. A limit of 0 uses the server's
+// default; before is the previous page's NextCursor, or nil for the newest.
+func (c *Client) Messages(ctx context.Context, limit int, before *uuid.UUID) (*MessagePage, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if before != nil {
+		q.Set("before", before.String())
+	}
+	var page MessagePage
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/chat", query: q, idempotent: true}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) PostMessage(ctx context.Context, body string) (*Message, error) {
+	var msg Message
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/chat", body: map[string]string{"body": body}}, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *Client) DeleteMessage(ctx context.Context, messageID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/chat/" + messageID.String(), idempotent: true}, nil)
+}
+
+// Spectate switches the player to a spectator slot.
+func (c *Client) Spectate(ctx context.Context) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/spectate"}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+// Play switches a spectator to a player slot.
+func (c *Client) Play(ctx context.Context) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/play"}, &party); err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (c *Client) SetReady(ctx context.Context, ready bool) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/ready", body: map[string]bool{"ready": ready}, idempotent: true}, nil)
+}
+
+// Heartbeat keeps the player online. status may be PresenceAway, or empty
+// to keep the last reported one. It returns the player's presence.
+func (c *Client) Heartbeat(ctx context.Context, status string) (string, error) {
+	var body any
+	if status != "" {
+		body = map[string]string{"status": status}
+	}
+	var out struct {
+		Presence string `json:"presence"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/parties/heartbeat", body: body, idempotent: true}, &out); err != nil {
+		return "", err
+	}
+	return out.Presence, nil
+}
+
+func (c *Client) Blocks(ctx context.Context) ([]Block, error) {
+	var out struct {
+		Blocks []Block `json:"blocks"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/parties/blocks", idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.Blocks, nil
+}
+
+func (c *Client) BlockAccount(ctx context.Context, accountID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/parties/blocks", body: accountBody(accountID), idempotent: true}, nil)
+}
+
+func (c *Client) UnblockAccount(ctx context.Context, accountID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/parties/blocks/" + accountID.String(), idempotent: true}, nil)
+}
+
+func accountBody(accountID uuid.UUID) map[string]uuid.UUID {
+	return map[string]uuid.UUID{"account_id": accountID}
+}
+
+// updateParty decodes the party a change returns, allowing for the
+// fallback body sent when it can't be re-read.
+func (c *Client) updateParty(ctx context.Context, cl call) (*Party, error) {
+	var party Party
+	if err := c.do(ctx, cl, &party); err != nil {
+		return nil, err
+	}
+	if party.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &party, nil
+}
+
+func (c *Client) split(ctx context.Context, cl call) ([]*Party, error) {
+	var out struct {
+		Parties []*Party `json:"parties"`
+	}
+	if err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return out.Parties, nil
+}
+
+func (c *Client) settings(ctx context.Context, cl call) (PermissionPolicy, error) {
+	var out struct {
+		Permissions PermissionPolicy `json:"permissions"`
+	}
+	if err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return out.Permissions, nil
+}
+
+func (c *Client) metadata(ctx context.Context, path string, m Metadata) (Metadata, error) {
+	var out struct {
+		Metadata Metadata `json:"metadata"`
+	}
+	body := map[string]Metadata{"metadata": m}
+	if err := c.do(ctx, call{method: http.MethodPatch, path: path, body: body, idempotent: true}, &out); err != nil {
+		return nil, err
+	}
+	return out.Metadata, nil
+}
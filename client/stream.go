@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrFellBehind ends a stream whose events the client didn't read fast
+// enough. Subscribe again to get a fresh snapshot.
+var ErrFellBehind = errors.New("hand: event stream fell behind")
+
+// Update is one message on the event stream: either a snapshot of the
+// player's party, sent on connect and whenever they join or leave one, or
+// an event.
+type Update struct {
+	// Snapshot marks a snapshot; Party is nil when the player is not in a
+	// party.
+	Snapshot bool
+	Party    *Party
+	Event    *Event
+}
+
+// Stream is an open event stream. Recv must not be called concurrently.
+type Stream struct {
+	conn      *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Subscribe opens the player's event stream (GET /v1/parties/ws). The stream
+// ends when ctx is done or Close is called. An open stream counts as a
+// heartbeat.
+func (c *Client) Subscribe(ctx context.Context) (*Stream, error) {
+	u := c.BaseURL + apiVersion + "/parties/ws"
+	if rest, ok := strings.CutPrefix(u, "http"); ok {
+		u = "ws" + rest
+	}
+	header := http.Header{}
+	c.playerHeaders(header)
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u, header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+			return nil, responseError(resp)
+		}
+		return nil, err
+	}
+
+	s := &Stream{conn: conn, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// socketFrame mirrors the server's frames.
+type socketFrame struct {
+	Type  string          `json:"type"`
+	Event *Event          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Recv blocks until the next update. It returns ErrFellBehind if the
+// server dropped the stream, and the connection's error once it closes.
+func (s *Stream) Recv() (*Update, error) {
+	for {
+		var frame socketFrame
+		if err := s.conn.ReadJSON(&frame); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseTryAgainLater) {
+				return nil, ErrFellBehind
+			}
+			return nil, err
+		}
+
+		switch frame.Type {
+		case "party":
+			u := &Update{Snapshot: true}
+			if len(frame.Data) > 0 && string(frame.Data) != "null" {
+				u.Party = new(Party)
+				if err := json.Unmarshal(frame.Data, u.Party); err != nil {
+					return nil, err
+				}
+			}
+			return u, nil
+		case "event":
+			if frame.Event != nil {
+				return &Update{Event: frame.Event}, nil
+			}
+		}
+		// Command results and errors don't concern a subscriber.
+	}
+}
+
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/google/uuid"
+)
+
+// The API's types are the server's own, so they can't drift apart.
+type (
+	Party            = models.Party
+	PartyMember      = models.PartyMember
+	Message          = models.PartyMessage
+	Ban              = models.PartyBan
+	Block            = models.Block
+	Metadata         = models.Metadata
+	PermissionPolicy = models.PermissionPolicy
+	Event            = events.Event
+	MergeProposal    = models.PartyMerge
+	Group            = models.PartyGroup
+	Invite           = models.PartyInvite
+)
+
+const (
+	RoleOwner     = models.RoleOwner
+	RoleModerator = models.RoleModerator
+	RoleMember    = models.RoleMember
+
+	MemberPlayer    = models.MemberPlayer
+	MemberSpectator = models.MemberSpectator
+
+	PresenceOnline  = models.PresenceOnline
+	PresenceAway    = models.PresenceAway
+	PresenceOffline = models.PresenceOffline
+)
+
+// Event types.
+const (
+	PartyCreated          = events.PartyCreated
+	PartyDisbanded        = events.PartyDisbanded
+	PartyOwnerChanged     = events.PartyOwnerChanged
+	PartyInviteChanged    = events.PartyInviteChanged
+	PartySettingsChanged  = events.PartySettingsChanged
+	PartyMetadataChanged  = events.PartyMetadataChanged
+	PartyProfileChanged   = events.PartyProfileChanged
+	PartyExpiring         = events.PartyExpiring
+	PartyLocked           = events.PartyLocked
+	PartyUnlocked         = events.PartyUnlocked
+	PartyMergeProposed    = events.PartyMergeProposed
+	PartyMergeCancelled   = events.PartyMergeCancelled
+	PartyMerged           = events.PartyMerged
+	PartySplit            = events.PartySplit
+	PartyGroupCreated     = events.PartyGroupCreated
+	PartyGroupDeleted     = events.PartyGroupDeleted
+	MemberJoined          = events.MemberJoined
+	MemberLeft            = events.MemberLeft
+	MemberKicked          = events.MemberKicked
+	MemberRoleChanged     = events.MemberRoleChanged
+	MemberReady           = events.MemberReady
+	MemberPresence        = events.MemberPresence
+	MemberMetadataChanged = events.MemberMetadataChanged
+	MemberGroupChanged    = events.MemberGroupChanged
+	MemberTypeChanged     = events.MemberTypeChanged
+	MemberInvited         = events.MemberInvited
+	MemberInviteCancelled = events.MemberInviteCancelled
+	ChatMessage           = events.ChatMessage
+	ChatDeleted           = events.ChatDeleted
+)
+
+// Kick describes a kick, optionally with a ban. A zero BanDuration bans
+// for as long as the party exists.
+type Kick struct {
+	AccountID   uuid.UUID
+	Ban         bool
+	BanDuration time.Duration
+}
+
+// ProfileUpdate changes the fields that are set; an empty string clears
+// a field.
+type ProfileUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Emblem      *string `json:"emblem,omitempty"`
+}
+
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Emblem      string `json:"emblem"`
+}
+
+// MessagePage is one page of chat history, newest first. Pass NextCursor
+// as Before to fetch the next page; it is nil on the last page.
+type MessagePage struct {
+	Messages   []Message  `json:"messages"`
+	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
+}
+
+// MergeProposals are a party's pending merges: the one it proposed, if
+// any, and those proposed to it.
+type MergeProposals struct {
+	Outgoing *MergeProposal  `json:"outgoing,omitempty"`
+	Incoming []MergeProposal `json:"incoming"`
+}
+
+// Invites are the player's pending invites and those sent by their party.
+type Invites struct {
+	Incoming []Invite `json:"incoming"`
+	Outgoing []Invite `json:"outgoing"`
+}
+
+// SplitGroup is one party a split creates. Owner must be one of Members.
+type SplitGroup struct {
+	OwnerID uuid.UUID   `json:"owner_id"`
+	Members []uuid.UUID `json:"members"`
+}
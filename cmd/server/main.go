@@ -4,29 +4,136 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"strings"
+	"time"
 
+	"github.com/bananalabs-oss/hand/internal/blocks"
 	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/parties"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
 	"github.com/bananalabs-oss/hand/internal/router"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/bananalabs-oss/hand/internal/textfilter"
 	"github.com/bananalabs-oss/potassium/config"
 	"github.com/bananalabs-oss/potassium/database"
 	"github.com/bananalabs-oss/potassium/server"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	log.Printf("Starting Hand")
 
-	jwtSecret := config.RequireEnv("JWT_SECRET")
-	serviceToken := config.RequireEnv("SERVICE_TOKEN")
+	tenantRegistry := loadTenants()
 	databaseURL := config.EnvOrDefault("DATABASE_URL", "sqlite://hand.db")
 	host := config.EnvOrDefault("HOST", "0.0.0.0")
 	port := config.EnvOrDefault("PORT", "8003")
+	grpcPort := config.EnvOrDefault("GRPC_PORT", "9003")
+
+	rateLimits, err := ratelimit.ParseLimits(config.EnvOrDefault("RATE_LIMITS", ""))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMITS: %v", err)
+	}
+
+	inviteCodes, err := parties.NewInviteCodeFormat(
+		config.EnvOrDefault("INVITE_CODE_STYLE", parties.InviteCodeChars),
+		config.EnvOrDefaultInt("INVITE_CODE_LENGTH", 0),
+	)
+	if err != nil {
+		log.Fatalf("Invalid invite code format: %v", err)
+	}
+
+	chatMaxAge, err := time.ParseDuration(config.EnvOrDefault("CHAT_MAX_AGE", parties.DefaultChatRetention.MaxAge.String()))
+	if err != nil {
+		log.Fatalf("Invalid CHAT_MAX_AGE: %v", err)
+	}
+	chatRetention := parties.ChatRetention{
+		MaxMessages: config.EnvOrDefaultInt("CHAT_MAX_MESSAGES", parties.DefaultChatRetention.MaxMessages),
+		MaxAge:      chatMaxAge,
+	}
+
+	wsMessageLimit, err := ratelimit.ParseLimit(config.EnvOrDefault("WS_MESSAGE_LIMIT", parties.DefaultGatewayConfig.MessageLimit.String()))
+	if err != nil {
+		log.Fatalf("Invalid WS_MESSAGE_LIMIT: %v", err)
+	}
+	gateway := parties.GatewayConfig{
+		MaxConnections:           config.EnvOrDefaultInt("WS_MAX_CONNECTIONS", parties.DefaultGatewayConfig.MaxConnections),
+		MaxConnectionsPerAccount: config.EnvOrDefaultInt("WS_MAX_CONNECTIONS_PER_ACCOUNT", parties.DefaultGatewayConfig.MaxConnectionsPerAccount),
+		MessageLimit:             wsMessageLimit,
+	}
+	if origins := config.EnvOrDefault("WS_ORIGINS", ""); origins != "" {
+		gateway.AllowedOrigins = strings.Split(origins, ",")
+	}
+
+	presence := parties.DefaultPresenceConfig
+	for env, d := range map[string]*time.Duration{
+		"PRESENCE_AWAY_AFTER":    &presence.AwayAfter,
+		"PRESENCE_OFFLINE_AFTER": &presence.OfflineAfter,
+		"PRESENCE_OFFLINE_GRACE": &presence.OfflineGrace,
+		"PRESENCE_REAP_INTERVAL": &presence.ReapInterval,
+	} {
+		if *d, err = time.ParseDuration(config.EnvOrDefault(env, d.String())); err != nil {
+			log.Fatalf("Invalid %s: %v", env, err)
+		}
+	}
+
+	expiry := parties.DefaultExpiryConfig
+	for env, d := range map[string]*time.Duration{
+		"PARTY_IDLE_EXPIRY":       &expiry.IdleAfter,
+		"PARTY_EXPIRY_WARNING":    &expiry.WarnBefore,
+		"PARTY_ARCHIVE_RETENTION": &expiry.ArchiveRetention,
+		"PARTY_EXPIRY_INTERVAL":   &expiry.Interval,
+	} {
+		if *d, err = time.ParseDuration(config.EnvOrDefault(env, d.String())); err != nil {
+			log.Fatalf("Invalid %s: %v", env, err)
+		}
+	}
+
+	inviteTTL, err := time.ParseDuration(config.EnvOrDefault("INVITE_TTL", parties.DefaultInviteTTL.String()))
+	if err != nil {
+		log.Fatalf("Invalid INVITE_TTL: %v", err)
+	}
+
+	games, err := parties.ParseGames(config.EnvOrDefault("GAMES", ""))
+	if err != nil {
+		log.Fatalf("Invalid GAMES: %v", err)
+	}
+
+	var blockProvider blocks.Provider
+	if url := config.EnvOrDefault("BLOCKLIST_URL", ""); url != "" {
+		blockProvider = blocks.NewHTTPProvider(url)
+	}
+
+	var textFilter textfilter.Filter
+	if path := config.EnvOrDefault("NAME_WORDLIST", ""); path != "" {
+		wordList, err := textfilter.LoadWordList(path)
+		if err != nil {
+			log.Fatalf("Invalid NAME_WORDLIST: %v", err)
+		}
+		textFilter = wordList
+	}
 
 	log.Printf("Hand Configuration:")
 	log.Printf("  Host:     %s", host)
 	log.Printf("  Port:     %s", port)
+	log.Printf("  gRPC:     %s", grpcPort)
 	log.Printf("  Database: %s", databaseURL)
+	log.Printf("  Invites:  %d %s", inviteCodes.Length, inviteCodes.Style)
+	for _, t := range tenantRegistry.All() {
+		log.Printf("  Tenant %s: JWKS %q, issuer %q, audience %q, %d rate limit overrides", t.ID, t.JWKS, t.Issuer, t.Audience, len(t.Limits()))
+		for _, cred := range t.CredentialSummary() {
+			log.Printf("    Service credential %s", cred)
+		}
+	}
+	for name, game := range games {
+		log.Printf("  Game %s: max size %d, modes %v", name, game.MaxSize, game.Modes)
+	}
+	for name, limit := range rateLimits {
+		log.Printf("  Rate limit %s: %s", name, limit)
+	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	db, err := database.Connect(databaseURL)
 	if err != nil {
@@ -34,18 +141,128 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := addColumns(ctx, db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
 	if err := database.Migrate(ctx, db, []interface{}{
 		(*models.Party)(nil),
 		(*models.PartyMember)(nil),
+		(*models.PartyMessage)(nil),
+		(*models.PartyBan)(nil),
+		(*models.Block)(nil),
+		(*models.PartyMerge)(nil),
+		(*models.PartyGroup)(nil),
+		(*models.PartyInvite)(nil),
 	}, []database.Index{
 		{Name: "idx_party_members_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_members_unique ON party_members (party_id, account_id)"},
-		{Name: "idx_party_members_account", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_members_account ON party_members (account_id)"},
+		{Name: "idx_party_members_account", Query: "DROP INDEX IF EXISTS idx_party_members_account"},
+		{Name: "idx_party_members_game_account", Query: "DROP INDEX IF EXISTS idx_party_members_game_account"},
+		{Name: "idx_party_members_scope_account", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_members_scope_account ON party_members (tenant, game, account_id)"},
+		{Name: "idx_party_messages_party", Query: "CREATE INDEX IF NOT EXISTS idx_party_messages_party ON party_messages (party_id, created_at)"},
+		{Name: "idx_party_bans_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_bans_unique ON party_bans (party_id, account_id)"},
+		{Name: "idx_account_blocks_unique", Query: "DROP INDEX IF EXISTS idx_account_blocks_unique"},
+		{Name: "idx_account_blocks_blocked", Query: "DROP INDEX IF EXISTS idx_account_blocks_blocked"},
+		{Name: "idx_account_blocks_tenant_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_account_blocks_tenant_unique ON account_blocks (tenant, account_id, blocked_id)"},
+		{Name: "idx_account_blocks_tenant_blocked", Query: "CREATE INDEX IF NOT EXISTS idx_account_blocks_tenant_blocked ON account_blocks (tenant, blocked_id)"},
+		{Name: "idx_party_merges_target", Query: "CREATE INDEX IF NOT EXISTS idx_party_merges_target ON party_merges (target_id)"},
+		{Name: "idx_party_groups_party", Query: "CREATE INDEX IF NOT EXISTS idx_party_groups_party ON party_groups (party_id)"},
+		{Name: "idx_party_invites_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_invites_unique ON party_invites (party_id, account_id)"},
+		{Name: "idx_party_invites_scope_account", Query: "CREATE INDEX IF NOT EXISTS idx_party_invites_scope_account ON party_invites (tenant, game, account_id)"},
 	}); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	r := router.Setup(db, jwtSecret, serviceToken)
+	var grpcServer *grpc.Server
+	if grpcPort != "" {
+		grpcServer = grpc.NewServer(tenantRegistry.GRPCServerOptions()...)
+	}
+
+	r := router.Setup(ctx, db, router.Config{
+		Tenants:       tenantRegistry,
+		RateLimits:    rateLimits,
+		InviteCodes:   inviteCodes,
+		ChatRetention: chatRetention,
+		Gateway:       gateway,
+		Presence:      presence,
+		Expiry:        expiry,
+		BlockProvider: blockProvider,
+		TextFilter:    textFilter,
+		Games:         games,
+		InviteTTL:     inviteTTL,
+		GRPC:          grpcServer,
+	})
+
+	if grpcServer != nil {
+		grpcAddr := fmt.Sprintf("%s:%s", host, grpcPort)
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s: %v", grpcAddr, err)
+		}
+		go func() {
+			log.Printf("Hand gRPC listening on %s", grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("gRPC server error: %v", err)
+			}
+		}()
+	}
 
 	addr := fmt.Sprintf("%s:%s", host, port)
 	server.ListenAndShutdown(addr, r, "Hand")
+
+	if grpcServer != nil {
+		stopGRPC(grpcServer)
+	}
+}
+
+// stopGRPC waits for in-flight calls, but cuts open WatchParty streams off
+// after the same 5 seconds the HTTP server is given.
+func stopGRPC(srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		srv.Stop()
+	}
+}
+
+// loadTenants reads TENANTS_FILE when set. The JWT_* variables and
+// SERVICE_TOKEN configure the default tenant, and are required when there
+// is no file.
+func loadTenants() *tenants.Registry {
+	var list []tenants.Tenant
+	if path := config.EnvOrDefault("TENANTS_FILE", ""); path != "" {
+		var err error
+		if list, err = tenants.Load(path); err != nil {
+			log.Fatalf("Invalid TENANTS_FILE: %v", err)
+		}
+	}
+
+	def := tenants.Tenant{
+		ID:                tenants.Default,
+		JWTSecret:         config.EnvOrDefault("JWT_SECRET", ""),
+		JWTPreviousSecret: config.EnvOrDefault("JWT_PREVIOUS_SECRET", ""),
+		JWKS:              config.EnvOrDefault("JWT_JWKS", ""),
+		Issuer:            config.EnvOrDefault("JWT_ISSUER", ""),
+		Audience:          config.EnvOrDefault("JWT_AUDIENCE", ""),
+		ServiceToken:      config.EnvOrDefault("SERVICE_TOKEN", ""),
+	}
+	if len(list) == 0 || def.JWTSecret != "" || def.JWKS != "" || def.ServiceToken != "" {
+		if len(list) == 0 {
+			if def.JWKS == "" {
+				def.JWTSecret = config.RequireEnv("JWT_SECRET")
+			}
+			def.ServiceToken = config.RequireEnv("SERVICE_TOKEN")
+		}
+		list = append(list, def)
+	}
+
+	registry, err := tenants.New(list)
+	if err != nil {
+		log.Fatalf("Invalid tenant configuration: %v", err)
+	}
+	return registry
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bananalabs-oss/hand/internal/parties"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/uptrace/bun"
+)
+
+// column is a column added to a table after its first release.
+// database.Migrate only creates missing tables, so existing databases get
+// these through ALTER TABLE instead.
+type column struct {
+	Table      string
+	Name       string
+	Definition string
+}
+
+var addedColumns = []column{
+	{Table: "party_members", Name: "ready", Definition: "BOOLEAN NOT NULL DEFAULT false"},
+	{Table: "party_members", Name: "presence", Definition: "VARCHAR NOT NULL DEFAULT 'online'"},
+	{Table: "party_members", Name: "last_seen_at", Definition: "TIMESTAMP"},
+	{Table: "parties", Name: "permissions", Definition: "VARCHAR"},
+	{Table: "parties", Name: "metadata", Definition: "VARCHAR"},
+	{Table: "party_members", Name: "metadata", Definition: "VARCHAR"},
+	{Table: "parties", Name: "name", Definition: "VARCHAR"},
+	{Table: "parties", Name: "description", Definition: "VARCHAR"},
+	{Table: "parties", Name: "emblem", Definition: "VARCHAR"},
+	{Table: "parties", Name: "game", Definition: "VARCHAR NOT NULL DEFAULT '" + parties.DefaultGame + "'"},
+	{Table: "party_members", Name: "game", Definition: "VARCHAR NOT NULL DEFAULT '" + parties.DefaultGame + "'"},
+	{Table: "parties", Name: "tenant", Definition: "VARCHAR NOT NULL DEFAULT '" + tenants.Default + "'"},
+	{Table: "party_members", Name: "tenant", Definition: "VARCHAR NOT NULL DEFAULT '" + tenants.Default + "'"},
+	{Table: "account_blocks", Name: "tenant", Definition: "VARCHAR NOT NULL DEFAULT '" + tenants.Default + "'"},
+	{Table: "parties", Name: "locked_by", Definition: "VARCHAR"},
+	{Table: "parties", Name: "locked_until", Definition: "TIMESTAMP"},
+	{Table: "parties", Name: "archived_at", Definition: "TIMESTAMP"},
+	{Table: "parties", Name: "merged_into", Definition: "text"},
+	{Table: "parties", Name: "split_from", Definition: "text"},
+	{Table: "party_members", Name: "group_id", Definition: "text"},
+	{Table: "parties", Name: "spectator_slots", Definition: "INTEGER NOT NULL DEFAULT 0"},
+	{Table: "party_members", Name: "type", Definition: "VARCHAR NOT NULL DEFAULT 'player'"},
+}
+
+// addColumns adds any of addedColumns missing from tables that already
+// exist. Tables that don't exist yet are left for database.Migrate to
+// create whole, so this must run before it.
+func addColumns(ctx context.Context, db *bun.DB) error {
+	for _, col := range addedColumns {
+		var columns, matches int
+		err := db.QueryRowContext(ctx,
+			"SELECT COUNT(*), COUNT(CASE WHEN name = ? THEN 1 END) FROM pragma_table_info(?)",
+			col.Name, col.Table,
+		).Scan(&columns, &matches)
+		if err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", col.Table, err)
+		}
+		if columns == 0 || matches > 0 {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q %s", col.Table, col.Name, col.Definition)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", col.Table, col.Name, err)
+		}
+		log.Printf("Added column %s.%s", col.Table, col.Name)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package auth validates player JWTs. Tokens are the ones BananAuth issues
+// and Potassium's middleware accepts, HMAC-signed with a shared secret, or
+// RS256/EdDSA tokens checked against a JWKS document. Hand additionally
+// reads optional claims that scope a token to a tenant and a game.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GameKey is the context key JWTAuth stores the game claim under, next to
+// Potassium's "account_id" and "session_id".
+const GameKey = "game_claim"
+
+// TenantKey is the context key the resolved tenant ID is stored under, by
+// JWTAuth for players and by service authentication for backends.
+const TenantKey = "tenant"
+
+// ServiceKey is the context key service authentication stores the calling
+// credential's name under.
+const ServiceKey = "service"
+
+// algorithms are the signing algorithms Hand accepts.
+var algorithms = []string{"HS256", "HS384", "HS512", "RS256", "EdDSA"}
+
+// Verifier holds what one tenant's tokens are checked against.
+type Verifier struct {
+	// Secrets are the accepted HMAC keys. Listing the old and new secret
+	// while rotating keeps tokens signed with either valid.
+	Secrets [][]byte
+	// JWKS verifies RS256 and EdDSA tokens. Nil rejects them.
+	JWKS *KeySet
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+// Keys resolves the verifier for a token's tenant claim.
+type Keys interface {
+	// Verifier returns the canonical tenant ID and its verifier. An empty
+	// tenant is the deployment's default tenant.
+	Verifier(tenant string) (id string, v *Verifier, ok bool)
+}
+
+// Claims are BananAuth's claims plus the scoping claims Hand understands.
+type Claims struct {
+	middleware.Claims
+	// Tenant selects the studio the token was issued for, and so the keys
+	// it must be signed with. Empty is the default tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// Game binds the token to one game namespace. Empty tokens may act in
+	// any namespace the request names.
+	Game string `json:"game,omitempty"`
+}
+
+// JWTAuth is middleware.JWTAuth with Hand's extra claims, verifying each
+// token with its tenant's keys. It responds exactly as Potassium's
+// middleware does on failure.
+func JWTAuth(keys Keys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, tenant, err := extractAndValidate(c.Request.Context(), c.GetHeader("Authorization"), keys)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Set("account_id", claims.AccountID)
+		c.Set("session_id", claims.SessionID)
+		c.Set(TenantKey, tenant)
+		c.Set(GameKey, claims.Game)
+		c.Next()
+	}
+}
+
+// ParseToken validates a raw JWT and returns its claims and tenant ID. The
+// tenant claim is read before verification only to pick the keys; a token
+// naming another tenant fails because no key of that tenant signed it.
+func ParseToken(ctx context.Context, tokenString string, keys Keys) (*Claims, string, error) {
+	var (
+		tenant   string
+		verifier *Verifier
+	)
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		id, v, ok := keys.Verifier(token.Claims.(*Claims).Tenant)
+		if !ok {
+			return nil, fmt.Errorf("unknown tenant")
+		}
+		tenant, verifier = id, v
+		return v.keysFor(ctx, token)
+	}, jwt.WithValidMethods(algorithms))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, "", fmt.Errorf("invalid token claims")
+	}
+
+	var opts []jwt.ParserOption
+	if verifier.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(verifier.Issuer))
+	}
+	if verifier.Audience != "" {
+		opts = append(opts, jwt.WithAudience(verifier.Audience))
+	}
+	if len(opts) > 0 {
+		if err := jwt.NewValidator(opts...).Validate(claims); err != nil {
+			return nil, "", fmt.Errorf("invalid token: %w", err)
+		}
+	}
+	return claims, tenant, nil
+}
+
+// keysFor returns the keys a token may have been signed with, given its
+// algorithm. Keys of one kind are never used for another algorithm.
+func (v *Verifier) keysFor(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	var keys []jwt.VerificationKey
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		for _, secret := range v.Secrets {
+			keys = append(keys, secret)
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		if v.JWKS != nil {
+			kid, _ := token.Header["kid"].(string)
+			for _, key := range v.JWKS.keysFor(ctx, kid, token.Method.Alg()) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key for signing method %v", token.Header["alg"])
+	}
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+func extractAndValidate(ctx context.Context, authHeader string, keys Keys) (*Claims, string, error) {
+	if authHeader == "" {
+		return nil, "", fmt.Errorf("missing authorization header")
+	}
+
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return nil, "", fmt.Errorf("invalid authorization format, expected: Bearer <token>")
+	}
+
+	return ParseToken(ctx, parts[1], keys)
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is how long a loaded key set is trusted before it
+	// is reloaded, so newly published keys are picked up.
+	jwksRefreshInterval = 5 * time.Minute
+	// jwksMinRefetch bounds reloads triggered by tokens naming an unknown
+	// key, so a flood of bad tokens can't hammer the JWKS endpoint.
+	jwksMinRefetch = 30 * time.Second
+	jwksTimeout    = 5 * time.Second
+	maxJWKSSize    = 1 << 20
+)
+
+// KeySet holds the public keys of a JWKS document, read from a local file
+// or an http(s) URL. Every key in the document is accepted, which is how
+// keys are rotated: publish the new key next to the old one, switch the
+// issuer over, then drop the old key.
+type KeySet struct {
+	source string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        []jwk
+	loadedAt    time.Time
+	refreshLock sync.Mutex
+	attemptedAt time.Time
+}
+
+type jwk struct {
+	kid string
+	alg string
+	key any
+}
+
+// NewKeySet loads the JWKS document at source, a file path or an http(s)
+// URL. It fails if the document can't be read or holds no usable keys.
+func NewKeySet(source string) (*KeySet, error) {
+	s := &KeySet{source: source, client: &http.Client{Timeout: jwksTimeout}}
+	if err := s.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// keysFor returns the keys that may have signed a token with the given kid
+// and algorithm. A token without a kid is tried against every key of the
+// right type.
+func (s *KeySet) keysFor(ctx context.Context, kid, alg string) []any {
+	s.mu.RLock()
+	stale := time.Since(s.loadedAt) > jwksRefreshInterval
+	keys := s.match(kid, alg)
+	s.mu.RUnlock()
+
+	if stale || (len(keys) == 0 && kid != "") {
+		s.maybeRefresh(ctx)
+		s.mu.RLock()
+		keys = s.match(kid, alg)
+		s.mu.RUnlock()
+	}
+	return keys
+}
+
+// match must be called with s.mu held.
+func (s *KeySet) match(kid, alg string) []any {
+	var keys []any
+	for _, k := range s.keys {
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		switch k.key.(type) {
+		case *rsa.PublicKey:
+			if alg != "RS256" {
+				continue
+			}
+		case ed25519.PublicKey:
+			if alg != "EdDSA" {
+				continue
+			}
+		}
+		keys = append(keys, k.key)
+	}
+	return keys
+}
+
+// maybeRefresh reloads the key set unless another reload is running or one
+// was attempted too recently. A failed reload keeps the previous keys.
+func (s *KeySet) maybeRefresh(ctx context.Context) {
+	if !s.refreshLock.TryLock() {
+		return
+	}
+	defer s.refreshLock.Unlock()
+	if time.Since(s.attemptedAt) < jwksMinRefetch {
+		return
+	}
+	_ = s.refresh(ctx)
+}
+
+func (s *KeySet) refresh(ctx context.Context) error {
+	s.attemptedAt = time.Now()
+	data, err := s.read(ctx)
+	if err != nil {
+		return fmt.Errorf("read JWKS %s: %w", s.source, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("parse JWKS %s: %w", s.source, err)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jwksTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint returned %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// parseJWKS keeps the RSA and Ed25519 signing keys of a JWKS document and
+// skips any other kind.
+func parseJWKS(data []byte) ([]jwk, error) {
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var keys []jwk
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: invalid modulus", k.Kid)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("key %q: invalid exponent", k.Kid)
+			}
+			pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+			if pub.N.BitLen() < 2048 {
+				return nil, fmt.Errorf("key %q: RSA keys must be at least 2048 bits", k.Kid)
+			}
+			keys = append(keys, jwk{kid: k.Kid, alg: k.Alg, key: pub})
+		case k.Kty == "OKP" && k.Crv == "Ed25519":
+			x, err := base64.RawURLEncoding.DecodeString(k.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("key %q: invalid Ed25519 key", k.Kid)
+			}
+			keys = append(keys, jwk{kid: k.Kid, alg: k.Alg, key: ed25519.PublicKey(x)})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA or Ed25519 signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,76 @@
+// Package blocks lets Hand consult a block graph kept outside its own
+// database, such as a social service's friends and blocks lists. Blocks
+// players create through Hand's own API are stored by the parties package;
+// a Provider is checked in addition to those.
+package blocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Provider reports whether an account and any of a set of other accounts
+// have blocked one another, in either direction. Account IDs are only
+// unique within a tenant.
+type Provider interface {
+	Blocked(ctx context.Context, tenant string, accountID uuid.UUID, others []uuid.UUID) (bool, error)
+}
+
+// HTTPProvider asks an external service over HTTP. It POSTs
+//
+//	{"tenant": "studio", "account_id": "uuid", "others": ["uuid", ...]}
+//
+// to <BaseURL>/blocks/check and expects {"blocked": true|false} back.
+type HTTPProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+const defaultTimeout = 2 * time.Second
+
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+	return &HTTPProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Client:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (p *HTTPProvider) Blocked(ctx context.Context, tenant string, accountID uuid.UUID, others []uuid.UUID) (bool, error) {
+	if len(others) == 0 {
+		return false, nil
+	}
+
+	body, err := json.Marshal(map[string]any{"tenant": tenant, "account_id": accountID, "others": others})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/blocks/check", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("block provider returned %s", resp.Status)
+	}
+	var out struct {
+		Blocked bool `json:"blocked"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, fmt.Errorf("decode block provider response: %w", err)
+	}
+	return out.Blocked, nil
+}
@@ -0,0 +1,180 @@
+// Package events fans party changes out to live subscribers such as
+// WebSocket connections. Delivery is in-process and best effort: a
+// subscriber that falls behind is dropped and expected to reconnect and
+// resync from the REST API.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PartyCreated          = "party.created"
+	PartyDisbanded        = "party.disbanded"
+	PartyOwnerChanged     = "party.owner_changed"
+	PartyInviteChanged    = "party.invite_changed"
+	PartySettingsChanged  = "party.settings_changed"
+	PartyMetadataChanged  = "party.metadata_changed"
+	PartyProfileChanged   = "party.profile_changed"
+	PartyExpiring         = "party.expiring"
+	PartyLocked           = "party.locked"
+	PartyUnlocked         = "party.unlocked"
+	PartyMergeProposed    = "party.merge_proposed"
+	PartyMergeCancelled   = "party.merge_cancelled"
+	PartyMerged           = "party.merged"
+	PartySplit            = "party.split"
+	PartyGroupCreated     = "party.group_created"
+	PartyGroupDeleted     = "party.group_deleted"
+	MemberJoined          = "member.joined"
+	MemberLeft            = "member.left"
+	MemberKicked          = "member.kicked"
+	MemberRoleChanged     = "member.role_changed"
+	MemberReady           = "member.ready"
+	MemberPresence        = "member.presence"
+	MemberMetadataChanged = "member.metadata_changed"
+	MemberGroupChanged    = "member.group_changed"
+	MemberTypeChanged     = "member.type_changed"
+	MemberInvited         = "member.invited"
+	MemberInviteCancelled = "member.invite_cancelled"
+	ChatMessage           = "chat.message"
+	ChatDeleted           = "chat.deleted"
+)
+
+// subscriberBuffer is how many undelivered events a subscriber may hold
+// before it is dropped.
+const subscriberBuffer = 64
+
+// Event describes a change to a party. AccountID is the member the event is
+// about (the joiner, the kicked player, the new owner); ActorID is who
+// caused it. Tenant scopes the account topic and is never sent to clients.
+type Event struct {
+	Tenant    string    `json:"-"`
+	Type      string    `json:"type"`
+	PartyID   uuid.UUID `json:"party_id"`
+	AccountID uuid.UUID `json:"account_id"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	Data      any       `json:"data,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// PartyTopic receives every event for a party.
+func PartyTopic(partyID uuid.UUID) string {
+	return "party:" + partyID.String()
+}
+
+// AccountTopic receives events about an account, including ones for parties
+// the account is not (or no longer) a member of. Account IDs come from each
+// tenant's own auth service, so the topic is per tenant.
+func AccountTopic(tenant string, accountID uuid.UUID) string {
+	return "account:" + tenant + ":" + accountID.String()
+}
+
+type Hub struct {
+	mu     sync.Mutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{topics: make(map[string]map[*Subscription]struct{})}
+}
+
+// Publish delivers ev to subscribers of its party and account topics. Each
+// subscriber receives the event at most once.
+func (h *Hub) Publish(ev Event) {
+	if h == nil {
+		return
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[*Subscription]struct{})
+	for _, topic := range []string{PartyTopic(ev.PartyID), AccountTopic(ev.Tenant, ev.AccountID)} {
+		for sub := range h.topics[topic] {
+			if _, ok := seen[sub]; ok {
+				continue
+			}
+			seen[sub] = struct{}{}
+			select {
+			case sub.ch <- ev:
+			default:
+				h.drop(sub)
+			}
+		}
+	}
+}
+
+// Subscribe returns a subscription to the given topics.
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	sub := &Subscription{hub: h, ch: make(chan Event, subscriberBuffer)}
+	h.mu.Lock()
+	h.add(sub, topics)
+	h.mu.Unlock()
+	return sub
+}
+
+// add and drop must be called with h.mu held.
+func (h *Hub) add(sub *Subscription, topics []string) {
+	sub.topics = topics
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Subscription]struct{})
+		}
+		h.topics[topic][sub] = struct{}{}
+	}
+}
+
+func (h *Hub) drop(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	h.remove(sub)
+	sub.closed = true
+	close(sub.ch)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	for _, topic := range sub.topics {
+		delete(h.topics[topic], sub)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	sub.topics = nil
+}
+
+type Subscription struct {
+	hub    *Hub
+	ch     chan Event
+	topics []string
+	closed bool
+}
+
+// Events is closed when the subscription is closed or dropped for falling
+// behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Resubscribe replaces the subscription's topics.
+func (s *Subscription) Resubscribe(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.hub.remove(s)
+	s.hub.add(s, topics)
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,30 +9,181 @@ import (
 )
 
 const (
-	RoleOwner  = "owner"
-	RoleMember = "member"
+	RoleOwner     = "owner"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
 
-	DefaultMaxSize = 8
+	// Member types. Players count toward a party's MaxSize; spectators
+	// follow the party into sessions and count toward SpectatorSlots.
+	MemberPlayer    = "player"
+	MemberSpectator = "spectator"
+
+	PresenceOnline  = "online"
+	PresenceAway    = "away"
+	PresenceOffline = "offline"
+
+	DefaultMaxSize        = 8
+	DefaultSpectatorSlots = 2
+
+	MaxMessageLength = 500
+
+	MaxBlocks = 500
+
+	MaxGroups = 8
+
+	MaxNameLength        = 32
+	MaxGroupNameLength   = 32
+	MaxDescriptionLength = 200
+	MaxEmblemLength      = 32
+
+	MaxMetadataKeys        = 32
+	MaxMetadataKeyLength   = 64
+	MaxPartyMetadataBytes  = 4096
+	MaxMemberMetadataBytes = 1024
 )
 
+// PermissionPolicy maps a party action to the lowest role allowed to
+// perform it. Actions left out use the service defaults.
+type PermissionPolicy map[string]string
+
+// Metadata is free-form JSON that game clients attach to a party or member.
+// Hand stores it but never interprets it.
+type Metadata map[string]any
+
+// MarshalJSON renders nil metadata as an empty object.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]any(m))
+}
+
 type Party struct {
 	bun.BaseModel `bun:"table:parties,alias:p"`
 
-	ID         uuid.UUID `bun:"id,pk,type:text"            json:"id"`
-	OwnerID    uuid.UUID `bun:"owner_id,notnull,type:text"  json:"owner_id"`
-	InviteCode string    `bun:"invite_code,notnull,unique"  json:"invite_code"`
-	MaxSize    int       `bun:"max_size,notnull"            json:"max_size"`
-	CreatedAt  time.Time `bun:"created_at,nullzero,notnull" json:"created_at"`
-	UpdatedAt  time.Time `bun:"updated_at,nullzero,notnull" json:"updated_at"`
+	ID          uuid.UUID `bun:"id,pk,type:text"            json:"id"`
+	OwnerID     uuid.UUID `bun:"owner_id,notnull,type:text"  json:"owner_id"`
+	Tenant      string    `bun:"tenant,notnull"              json:"-"`
+	Game        string    `bun:"game,notnull"                json:"game"`
+	InviteCode  string    `bun:"invite_code,notnull,unique"  json:"invite_code,omitempty"`
+	MaxSize     int       `bun:"max_size,notnull"            json:"max_size"`
+	Name        string    `bun:"name,nullzero"               json:"name"`
+	Description string    `bun:"description,nullzero"        json:"description"`
+	Emblem      string    `bun:"emblem,nullzero"             json:"emblem"`
+	CreatedAt   time.Time `bun:"created_at,nullzero,notnull" json:"created_at"`
+	UpdatedAt   time.Time `bun:"updated_at,nullzero,notnull" json:"updated_at"`
+	Metadata    Metadata  `bun:"metadata"                    json:"metadata"`
+
+	// SpectatorSlots is how many spectators the party holds on top of
+	// MaxSize players.
+	SpectatorSlots int `bun:"spectator_slots,notnull,default:0" json:"spectator_slots"`
+
+	// LockedBy names the service credential holding the party's roster
+	// lock, which lapses at LockedUntil when set.
+	LockedBy    string     `bun:"locked_by,nullzero" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `bun:"locked_until"       json:"locked_until,omitempty"`
+
+	// Permissions holds the party's overrides only; see GET /parties/settings.
+	Permissions PermissionPolicy `bun:"permissions" json:"-"`
+
+	// ArchivedAt is set when the party was merged into MergedInto or split
+	// into parties whose SplitFrom names it. An archived party has no
+	// members but keeps its chat history.
+	ArchivedAt *time.Time `bun:"archived_at"           json:"archived_at,omitempty"`
+	MergedInto *uuid.UUID `bun:"merged_into,type:text" json:"merged_into,omitempty"`
+	SplitFrom  *uuid.UUID `bun:"split_from,type:text"  json:"split_from,omitempty"`
 
 	Members []PartyMember `bun:"rel:has-many,join:id=party_id" json:"members,omitempty"`
+	Groups  []PartyGroup  `bun:"rel:has-many,join:id=party_id" json:"groups,omitempty"`
 }
 
 type PartyMember struct {
 	bun.BaseModel `bun:"table:party_members,alias:pm"`
 
+	PartyID    uuid.UUID `bun:"party_id,notnull,type:text"            json:"party_id"`
+	AccountID  uuid.UUID `bun:"account_id,notnull,type:text"          json:"account_id"`
+	Tenant     string    `bun:"tenant,notnull"                        json:"-"`
+	Game       string    `bun:"game,notnull"                          json:"-"`
+	Role       string    `bun:"role,notnull"                          json:"role"`
+	Type       string    `bun:"type,notnull,default:'player'"         json:"type"`
+	Ready      bool      `bun:"ready,notnull,default:false"           json:"ready"`
+	Presence   string    `bun:"presence,notnull,default:'online'"     json:"presence"`
+	LastSeenAt time.Time `bun:"last_seen_at,nullzero"                 json:"last_seen_at"`
+	JoinedAt   time.Time `bun:"joined_at,nullzero,notnull"            json:"joined_at"`
+	Metadata   Metadata  `bun:"metadata"                              json:"metadata"`
+
+	// GroupID is the sub-group the member is in, if any.
+	GroupID *uuid.UUID `bun:"group_id,type:text" json:"group_id,omitempty"`
+}
+
+// PartyGroup is a named sub-group of a party, such as a team or squad,
+// holding up to MaxSize of its members.
+type PartyGroup struct {
+	bun.BaseModel `bun:"table:party_groups,alias:grp"`
+
+	ID        uuid.UUID `bun:"id,pk,type:text"             json:"id"`
+	PartyID   uuid.UUID `bun:"party_id,notnull,type:text"  json:"party_id"`
+	Name      string    `bun:"name,notnull"                json:"name"`
+	MaxSize   int       `bun:"max_size,notnull"            json:"max_size"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull" json:"created_at"`
+}
+
+type PartyMessage struct {
+	bun.BaseModel `bun:"table:party_messages,alias:msg"`
+
+	ID        uuid.UUID `bun:"id,pk,type:text"              json:"id"`
+	PartyID   uuid.UUID `bun:"party_id,notnull,type:text"   json:"party_id"`
+	AccountID uuid.UUID `bun:"account_id,notnull,type:text" json:"account_id"`
+	Body      string    `bun:"body,notnull"                 json:"body"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull"  json:"created_at"`
+}
+
+// PartyBan keeps AccountID out of a party. A nil ExpiresAt bans for as long
+// as the party exists.
+type PartyBan struct {
+	bun.BaseModel `bun:"table:party_bans,alias:ban"`
+
+	PartyID   uuid.UUID  `bun:"party_id,notnull,type:text"   json:"party_id"`
+	AccountID uuid.UUID  `bun:"account_id,notnull,type:text" json:"account_id"`
+	BannedBy  uuid.UUID  `bun:"banned_by,notnull,type:text"  json:"banned_by"`
+	ExpiresAt *time.Time `bun:"expires_at"                   json:"expires_at"`
+	CreatedAt time.Time  `bun:"created_at,nullzero,notnull"  json:"created_at"`
+}
+
+// Block records that AccountID has blocked BlockedID.
+type Block struct {
+	bun.BaseModel `bun:"table:account_blocks,alias:b"`
+
+	Tenant    string    `bun:"tenant,notnull"               json:"-"`
+	AccountID uuid.UUID `bun:"account_id,notnull,type:text" json:"-"`
+	BlockedID uuid.UUID `bun:"blocked_id,notnull,type:text" json:"account_id"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull"  json:"created_at"`
+}
+
+// PartyMerge is a pending proposal to move every member of SourceID into
+// TargetID. A party has at most one outgoing proposal.
+type PartyMerge struct {
+	bun.BaseModel `bun:"table:party_merges,alias:mg"`
+
+	SourceID   uuid.UUID `bun:"source_id,pk,type:text"        json:"source_id"`
+	TargetID   uuid.UUID `bun:"target_id,notnull,type:text"   json:"target_id"`
+	Tenant     string    `bun:"tenant,notnull"                json:"-"`
+	ProposedBy uuid.UUID `bun:"proposed_by,notnull,type:text" json:"proposed_by"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull"   json:"created_at"`
+	ExpiresAt  time.Time `bun:"expires_at,notnull"            json:"expires_at"`
+}
+
+// PartyInvite is a direct invite of AccountID into PartyID. Until it
+// expires, is declined or is revoked it holds one of the party's player
+// slots for them.
+type PartyInvite struct {
+	bun.BaseModel `bun:"table:party_invites,alias:inv"`
+
 	PartyID   uuid.UUID `bun:"party_id,notnull,type:text"   json:"party_id"`
-	AccountID uuid.UUID `bun:"account_id,notnull,type:text"  json:"account_id"`
-	Role      string    `bun:"role,notnull"                  json:"role"`
-	JoinedAt  time.Time `bun:"joined_at,nullzero,notnull"    json:"joined_at"`
-}
\ No newline at end of file
+	AccountID uuid.UUID `bun:"account_id,notnull,type:text" json:"account_id"`
+	Tenant    string    `bun:"tenant,notnull"               json:"-"`
+	Game      string    `bun:"game,notnull"                 json:"-"`
+	InvitedBy uuid.UUID `bun:"invited_by,notnull,type:text" json:"invited_by"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull"  json:"created_at"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"           json:"expires_at"`
+}
@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/google/uuid"
+)
+
+// access is how a route authenticates its caller.
+type access int
+
+const (
+	public access = iota
+	player
+	service
+)
+
+// route documents one route registered in router.Setup. Errors list the
+// codes the handler itself returns; those of the auth, game and rate limit
+// middleware are added according to access.
+type route struct {
+	method, path string
+	summary      string
+	access       access
+	scope        string
+	query        []param
+	body         any
+	// optionalBody marks bodies that may be omitted entirely.
+	optionalBody bool
+	status       int
+	response     any
+	// alternate is a second success shape, returned when the updated party
+	// can't be re-read.
+	alternate any
+	errors    []apiError
+	// unversioned routes sit outside the versioned API.
+	unversioned bool
+}
+
+type param struct {
+	name, description string
+}
+
+type apiError struct {
+	status int
+	code   string
+}
+
+// Request and response bodies. Handlers build most of these with gin.H;
+// these types only describe them.
+type (
+	messageResponse struct {
+		Message string `json:"message"`
+	}
+	healthResponse struct {
+		Status  string `json:"status"`
+		Service string `json:"service"`
+	}
+	accountRequest struct {
+		AccountID uuid.UUID `json:"account_id"`
+	}
+	joinRequest struct {
+		InviteCode string `json:"invite_code"`
+		Type       string `json:"type,omitempty"`
+	}
+	mergeRequest struct {
+		InviteCode string `json:"invite_code"`
+	}
+	kickRequest struct {
+		AccountID   uuid.UUID `json:"account_id"`
+		Ban         bool      `json:"ban,omitempty"`
+		BanDuration string    `json:"ban_duration,omitempty"`
+	}
+	bansResponse struct {
+		Bans []models.PartyBan `json:"bans"`
+	}
+	transferFallback struct {
+		Status string `json:"status"`
+	}
+	roleFallback struct {
+		Role string `json:"role"`
+	}
+	settingsBody struct {
+		Permissions models.PermissionPolicy `json:"permissions"`
+	}
+	profileRequest struct {
+		Name        string `json:"name,omitempty"`
+		Description string `json:"description,omitempty"`
+		Emblem      string `json:"emblem,omitempty"`
+	}
+	profileResponse struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Emblem      string `json:"emblem"`
+	}
+	metadataBody struct {
+		Metadata models.Metadata `json:"metadata"`
+	}
+	inviteResponse struct {
+		InviteCode string `json:"invite_code"`
+	}
+	messagesResponse struct {
+		Messages   []models.PartyMessage `json:"messages"`
+		NextCursor *uuid.UUID            `json:"next_cursor,omitempty"`
+	}
+	chatRequest struct {
+		Body string `json:"body"`
+	}
+	readyBody struct {
+		Ready bool `json:"ready"`
+	}
+	heartbeatRequest struct {
+		Status string `json:"status,omitempty"`
+	}
+	presenceResponse struct {
+		Presence string `json:"presence"`
+	}
+	blocksResponse struct {
+		Blocks []models.Block `json:"blocks"`
+	}
+	batchPartiesRequest struct {
+		PartyIDs []uuid.UUID `json:"party_ids"`
+		Game     string      `json:"game,omitempty"`
+	}
+	batchPlayersRequest struct {
+		AccountIDs []uuid.UUID `json:"account_ids"`
+		Game       string      `json:"game,omitempty"`
+	}
+	batchResponse struct {
+		Parties map[string]models.Party `json:"parties"`
+	}
+	lockRequest struct {
+		TTL string `json:"ttl,omitempty"`
+	}
+	groupRequest struct {
+		Name    string `json:"name"`
+		MaxSize int    `json:"max_size"`
+	}
+	assignGroupRequest struct {
+		AccountID uuid.UUID  `json:"account_id"`
+		GroupID   *uuid.UUID `json:"group_id"`
+	}
+	splitGroup struct {
+		OwnerID uuid.UUID   `json:"owner_id"`
+		Members []uuid.UUID `json:"members"`
+	}
+	splitRequest struct {
+		Groups []splitGroup `json:"groups"`
+	}
+	splitResponse struct {
+		Parties []models.Party `json:"parties"`
+	}
+	mergeProposals struct {
+		Outgoing *models.PartyMerge  `json:"outgoing,omitempty"`
+		Incoming []models.PartyMerge `json:"incoming"`
+	}
+	partyInvites struct {
+		Incoming []models.PartyInvite `json:"incoming"`
+		Outgoing []models.PartyInvite `json:"outgoing"`
+	}
+)
+
+func errs(status int, codes ...string) []apiError {
+	out := make([]apiError, len(codes))
+	for i, code := range codes {
+		out[i] = apiError{status, code}
+	}
+	return out
+}
+
+func join(lists ...[]apiError) []apiError {
+	var out []apiError
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
+
+// Errors shared by many player routes.
+var (
+	notInParty = errs(http.StatusNotFound, "not_in_party")
+	denied     = join(errs(http.StatusForbidden, "not_owner", "not_permitted"), errs(http.StatusInternalServerError, "fetch_failed"))
+	badRequest = errs(http.StatusBadRequest, "invalid_request")
+	badID      = errs(http.StatusBadRequest, "invalid_id")
+)
+
+var gameQuery = param{"game", "Game namespace"}
+
+// routes must list every route router.Setup registers; Covers checks it.
+// Paths are unversioned; build documents each under the current version
+// too.
+var routes = []route{
+	{method: "GET", path: "/health", summary: "Service health check", access: public, status: 200, response: healthResponse{}, unversioned: true},
+	{method: "GET", path: "/openapi.json", summary: "This document", access: public, status: 200, response: map[string]any{}, unversioned: true},
+
+	{method: "GET", path: "/parties/ws", summary: "Open the party event WebSocket", access: player,
+		status: http.StatusSwitchingProtocols,
+		errors: errs(http.StatusServiceUnavailable, "gateway_disabled", "too_many_connections")},
+
+	{method: "POST", path: "/parties", summary: "Create a party", access: player, status: 201, response: models.Party{},
+		errors: join(errs(http.StatusConflict, "already_in_party"), errs(http.StatusInternalServerError, "create_failed"))},
+	{method: "GET", path: "/parties/mine", summary: "Get your current party", access: player, status: 200, response: models.Party{},
+		errors: join(notInParty, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "POST", path: "/parties/join", summary: "Join a party by invite code", access: player, body: joinRequest{}, status: 200, response: models.Party{},
+		errors: join(badRequest, errs(http.StatusNotFound, "invalid_code"), errs(http.StatusForbidden, "banned", "blocked"),
+			errs(http.StatusConflict, "already_in_party", "party_locked", "party_full", "spectators_full"), errs(http.StatusInternalServerError, "fetch_failed", "join_failed"))},
+	{method: "POST", path: "/parties/leave", summary: "Leave your party; the owner leaving disbands it", access: player, status: 200, response: messageResponse{},
+		errors: join(notInParty, errs(http.StatusInternalServerError, "leave_failed", "disband_failed"))},
+	{method: "POST", path: "/parties/kick", summary: "Kick a member, optionally banning them", access: player, body: kickRequest{}, status: 200, response: messageResponse{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusConflict, "party_locked"), errs(http.StatusInternalServerError, "kick_failed"))},
+	{method: "GET", path: "/parties/bans", summary: "List active bans", access: player, status: 200, response: bansResponse{},
+		errors: join(notInParty, denied)},
+	{method: "DELETE", path: "/parties/bans/:accountId", summary: "Lift a ban", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, notInParty, errs(http.StatusNotFound, "not_banned"), denied, errs(http.StatusInternalServerError, "unban_failed"))},
+	{method: "POST", path: "/parties/transfer", summary: "Transfer ownership", access: player, body: accountRequest{}, status: 200, response: models.Party{}, alternate: transferFallback{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusInternalServerError, "transfer_failed"))},
+	{method: "POST", path: "/parties/promote", summary: "Promote a member to moderator", access: player, body: accountRequest{}, status: 200, response: models.Party{}, alternate: roleFallback{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusConflict, "invalid_role"), errs(http.StatusInternalServerError, "role_change_failed"))},
+	{method: "POST", path: "/parties/demote", summary: "Demote a moderator to member", access: player, body: accountRequest{}, status: 200, response: models.Party{}, alternate: roleFallback{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusConflict, "invalid_role"), errs(http.StatusInternalServerError, "role_change_failed"))},
+	{method: "GET", path: "/parties/settings", summary: "Get the party's permission policy", access: player, status: 200, response: settingsBody{},
+		errors: join(notInParty, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "PATCH", path: "/parties/settings", summary: "Change the permission policy", access: player, body: settingsBody{}, status: 200, response: settingsBody{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_permission", "invalid_role"), notInParty, denied, errs(http.StatusInternalServerError, "update_failed"))},
+	{method: "PATCH", path: "/parties/profile", summary: "Change the party's name, description or emblem", access: player, body: profileRequest{}, status: 200, response: profileResponse{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_name", "invalid_description", "invalid_emblem", "name_rejected"), notInParty, denied, errs(http.StatusInternalServerError, "update_failed"))},
+	{method: "PATCH", path: "/parties/metadata", summary: "Merge keys into the party's metadata", access: player, body: metadataBody{}, status: 200, response: metadataBody{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_metadata", "invalid_mode"), errs(http.StatusRequestEntityTooLarge, "metadata_too_large"), notInParty, denied, errs(http.StatusInternalServerError, "update_failed"))},
+	{method: "PATCH", path: "/parties/metadata/me", summary: "Merge keys into your member metadata", access: player, body: metadataBody{}, status: 200, response: metadataBody{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_metadata"), errs(http.StatusRequestEntityTooLarge, "metadata_too_large"), notInParty, errs(http.StatusInternalServerError, "update_failed"))},
+	{method: "DELETE", path: "/parties", summary: "Disband the party", access: player, status: 200, response: messageResponse{},
+		errors: join(notInParty, denied, errs(http.StatusInternalServerError, "disband_failed"))},
+	{method: "POST", path: "/parties/invite", summary: "Regenerate the invite code", access: player, status: 200, response: inviteResponse{},
+		errors: join(notInParty, denied, errs(http.StatusInternalServerError, "regenerate_failed"))},
+	{method: "POST", path: "/parties/invites", summary: "Invite a player, reserving a slot for them", access: player, body: accountRequest{}, status: 201, response: models.PartyInvite{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusForbidden, "banned", "blocked"),
+			errs(http.StatusConflict, "already_in_party", "party_locked", "party_full"), errs(http.StatusInternalServerError, "fetch_failed", "invite_failed"))},
+	{method: "GET", path: "/parties/invites", summary: "List your pending invites and your party's", access: player, status: 200, response: partyInvites{},
+		errors: errs(http.StatusInternalServerError, "fetch_failed")},
+	{method: "DELETE", path: "/parties/invites/:accountId", summary: "Revoke an invite, freeing its slot", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, notInParty, denied, errs(http.StatusNotFound, "no_invite"), errs(http.StatusInternalServerError, "invite_failed"))},
+	{method: "POST", path: "/parties/invites/:partyId/accept", summary: "Accept an invite, joining that party", access: player, status: 200, response: models.Party{},
+		errors: join(badID, errs(http.StatusNotFound, "no_invite"), errs(http.StatusForbidden, "banned", "blocked"),
+			errs(http.StatusConflict, "already_in_party", "party_locked", "party_full"), errs(http.StatusInternalServerError, "fetch_failed", "join_failed"))},
+	{method: "POST", path: "/parties/invites/:partyId/decline", summary: "Decline an invite", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, errs(http.StatusNotFound, "no_invite"), errs(http.StatusInternalServerError, "invite_failed"))},
+	{method: "POST", path: "/parties/merge", summary: "Propose merging your party into another", access: player, body: mergeRequest{}, status: 201, response: models.PartyMerge{},
+		errors: join(badRequest, notInParty, denied, errs(http.StatusNotFound, "invalid_code"), errs(http.StatusConflict, "party_locked", "party_full"), errs(http.StatusInternalServerError, "merge_failed"))},
+	{method: "GET", path: "/parties/merge", summary: "List your party's pending merge proposals", access: player, status: 200, response: mergeProposals{},
+		errors: join(notInParty, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "DELETE", path: "/parties/merge", summary: "Withdraw your party's merge proposal", access: player, status: 200, response: messageResponse{},
+		errors: join(notInParty, denied, errs(http.StatusNotFound, "no_merge_proposal"), errs(http.StatusInternalServerError, "merge_failed"))},
+	{method: "POST", path: "/parties/merge/:partyId/accept", summary: "Accept a merge, moving the proposing party's members into yours", access: player, status: 200, response: models.Party{},
+		errors: join(badID, notInParty, denied, errs(http.StatusNotFound, "no_merge_proposal"), errs(http.StatusForbidden, "banned", "blocked"),
+			errs(http.StatusConflict, "party_locked", "party_full", "roster_changed"), errs(http.StatusInternalServerError, "merge_failed"))},
+	{method: "POST", path: "/parties/merge/:partyId/decline", summary: "Decline a merge proposal", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, notInParty, denied, errs(http.StatusNotFound, "no_merge_proposal"), errs(http.StatusInternalServerError, "merge_failed"))},
+	{method: "POST", path: "/parties/split", summary: "Split your party into new parties", access: player, body: splitRequest{}, status: 201, response: splitResponse{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_partition"), notInParty, denied, errs(http.StatusConflict, "party_locked", "roster_changed"), errs(http.StatusInternalServerError, "split_failed"))},
+	{method: "POST", path: "/parties/groups", summary: "Create a sub-group", access: player, body: groupRequest{}, status: 201, response: models.PartyGroup{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_name", "name_rejected"), notInParty, denied, errs(http.StatusConflict, "too_many_groups", "group_exists"), errs(http.StatusInternalServerError, "create_failed"))},
+	{method: "DELETE", path: "/parties/groups/:groupId", summary: "Delete a sub-group", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, notInParty, errs(http.StatusNotFound, "group_not_found"), denied, errs(http.StatusInternalServerError, "delete_failed"))},
+	{method: "POST", path: "/parties/groups/:groupId/join", summary: "Join a sub-group", access: player, status: 200, response: models.Party{},
+		errors: join(badID, notInParty, errs(http.StatusNotFound, "group_not_found"), errs(http.StatusConflict, "party_locked", "group_full", "is_spectator"), errs(http.StatusInternalServerError, "fetch_failed", "group_change_failed"))},
+	{method: "POST", path: "/parties/groups/leave", summary: "Leave your sub-group", access: player, status: 200, response: models.Party{},
+		errors: join(notInParty, errs(http.StatusConflict, "party_locked"), errs(http.StatusInternalServerError, "fetch_failed", "group_change_failed"))},
+	{method: "POST", path: "/parties/groups/assign", summary: "Put a member into a sub-group, or out of theirs", access: player, body: assignGroupRequest{}, status: 200, response: models.Party{},
+		errors: join(badRequest, notInParty, errs(http.StatusNotFound, "group_not_found"), denied, errs(http.StatusConflict, "party_locked", "group_full", "is_spectator"), errs(http.StatusInternalServerError, "group_change_failed"))},
+	{method: "GET", path: "/parties/chat", summary: "Get chat history, newest first", access: player, status: 200, response: messagesResponse{},
+		query:  []param{{"limit", "Page size, 1–100 (default 50)"}, {"before", "next_cursor of the previous page"}},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_cursor"), notInParty, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "POST", path: "/parties/chat", summary: "Post a chat message", access: player, body: chatRequest{}, status: 201, response: models.PartyMessage{},
+		errors: join(errs(http.StatusBadRequest, "invalid_request", "invalid_message"), notInParty, errs(http.StatusInternalServerError, "post_failed"))},
+	{method: "DELETE", path: "/parties/chat/:messageId", summary: "Delete a chat message", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, notInParty, errs(http.StatusNotFound, "not_found"), denied, errs(http.StatusInternalServerError, "delete_failed"))},
+	{method: "POST", path: "/parties/ready", summary: "Set your ready state", access: player, body: readyBody{}, status: 200, response: readyBody{},
+		errors: join(badRequest, notInParty, errs(http.StatusInternalServerError, "ready_failed"))},
+	{method: "POST", path: "/parties/spectate", summary: "Switch to spectator", access: player, status: 200, response: models.Party{},
+		errors: join(notInParty, errs(http.StatusConflict, "invalid_type", "party_locked", "spectators_full"), errs(http.StatusInternalServerError, "fetch_failed", "type_change_failed"))},
+	{method: "POST", path: "/parties/play", summary: "Switch to player", access: player, status: 200, response: models.Party{},
+		errors: join(notInParty, errs(http.StatusConflict, "invalid_type", "party_locked", "party_full"), errs(http.StatusInternalServerError, "fetch_failed", "type_change_failed"))},
+	{method: "POST", path: "/parties/heartbeat", summary: "Report presence", access: player, body: heartbeatRequest{}, optionalBody: true, status: 200, response: presenceResponse{},
+		errors: join(badRequest, notInParty, errs(http.StatusInternalServerError, "heartbeat_failed"))},
+	{method: "GET", path: "/parties/blocks", summary: "List players you've blocked", access: player, status: 200, response: blocksResponse{},
+		errors: errs(http.StatusInternalServerError, "fetch_failed")},
+	{method: "POST", path: "/parties/blocks", summary: "Block a player", access: player, body: accountRequest{}, status: 200, response: messageResponse{},
+		errors: join(badRequest, errs(http.StatusConflict, "too_many_blocks"), errs(http.StatusInternalServerError, "block_failed"))},
+	{method: "DELETE", path: "/parties/blocks/:accountId", summary: "Unblock a player", access: player, status: 200, response: messageResponse{},
+		errors: join(badID, errs(http.StatusNotFound, "not_blocked"), errs(http.StatusInternalServerError, "unblock_failed"))},
+
+	{method: "POST", path: "/internal/parties/batch", summary: "Look up to 100 parties", access: service, scope: tenants.ScopeRead, body: batchPartiesRequest{}, status: 200, response: batchResponse{},
+		errors: join(badRequest, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "GET", path: "/internal/parties/:partyId", summary: "Get a party with its members", access: service, scope: tenants.ScopeRead, query: []param{gameQuery}, status: 200, response: models.Party{},
+		errors: join(badID, errs(http.StatusNotFound, "not_found"))},
+	{method: "POST", path: "/internal/parties/:partyId/lock", summary: "Lock a party's roster", access: service, scope: tenants.ScopeLock, body: lockRequest{}, optionalBody: true, status: 200, response: models.Party{},
+		errors: join(errs(http.StatusBadRequest, "invalid_id", "invalid_request"), errs(http.StatusNotFound, "not_found"), errs(http.StatusConflict, "already_locked", "party_archived"), errs(http.StatusInternalServerError, "lock_failed"))},
+	{method: "DELETE", path: "/internal/parties/:partyId/lock", summary: "Release a party's roster lock", access: service, scope: tenants.ScopeLock, status: 200, response: models.Party{},
+		errors: join(badID, errs(http.StatusNotFound, "not_found"), errs(http.StatusConflict, "already_locked"), errs(http.StatusInternalServerError, "unlock_failed"))},
+	{method: "POST", path: "/internal/parties/:partyId/split", summary: "Split a party into new parties", access: service, scope: tenants.ScopeLock, body: splitRequest{}, status: 201, response: splitResponse{},
+		errors: join(errs(http.StatusBadRequest, "invalid_id", "invalid_request", "invalid_partition"), errs(http.StatusNotFound, "not_found"), errs(http.StatusConflict, "party_locked", "roster_changed"), errs(http.StatusInternalServerError, "split_failed", "fetch_failed"))},
+	{method: "POST", path: "/internal/parties/player/batch", summary: "Look up the parties of up to 100 players", access: service, scope: tenants.ScopeRead, body: batchPlayersRequest{}, status: 200, response: batchResponse{},
+		errors: join(badRequest, errs(http.StatusInternalServerError, "fetch_failed"))},
+	{method: "GET", path: "/internal/parties/player/:userId", summary: "Get a player's current party", access: service, scope: tenants.ScopeRead, query: []param{gameQuery}, status: 200, response: models.Party{},
+		errors: join(badID, errs(http.StatusNotFound, "not_in_party"), errs(http.StatusInternalServerError, "fetch_failed"))},
+}
@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+)
+
+var (
+	uuidType  = reflect.TypeOf(uuid.UUID{})
+	timeType  = reflect.TypeOf(time.Time{})
+	modelsPkg = reflect.TypeOf(models.Party{}).PkgPath()
+)
+
+// enums narrows string fields that only take a few values, by "Type.json".
+var enums = map[string][]any{
+	"PartyMember.role":     {models.RoleOwner, models.RoleModerator, models.RoleMember},
+	"PartyMember.presence": {models.PresenceOnline, models.PresenceAway, models.PresenceOffline},
+}
+
+// schemaOf describes how encoding/json renders a value of type t. Structs
+// from the models package become shared components; other structs, which
+// mirror one handler's gin.H, are inlined.
+func (b *builder) schemaOf(t reflect.Type) *openapi3.SchemaRef {
+	switch t {
+	case uuidType:
+		return openapi3.NewSchemaRef("", openapi3.NewUUIDSchema())
+	case timeType:
+		return openapi3.NewSchemaRef("", openapi3.NewDateTimeSchema())
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		ref := b.schemaOf(t.Elem())
+		if ref.Ref != "" {
+			return ref
+		}
+		return openapi3.NewSchemaRef("", ref.Value.WithNullable())
+	case reflect.String:
+		return openapi3.NewSchemaRef("", openapi3.NewStringSchema())
+	case reflect.Bool:
+		return openapi3.NewSchemaRef("", openapi3.NewBoolSchema())
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return openapi3.NewSchemaRef("", openapi3.NewIntegerSchema())
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewSchemaRef("", openapi3.NewFloat64Schema())
+	case reflect.Slice:
+		s := openapi3.NewArraySchema()
+		s.Items = b.schemaOf(t.Elem())
+		return openapi3.NewSchemaRef("", s)
+	case reflect.Map:
+		s := openapi3.NewObjectSchema()
+		s.AdditionalProperties = openapi3.AdditionalProperties{Schema: b.schemaOf(t.Elem())}
+		return openapi3.NewSchemaRef("", s)
+	case reflect.Interface:
+		// Any JSON value, null included.
+		return openapi3.NewSchemaRef("", openapi3.NewSchema().WithNullable())
+	case reflect.Struct:
+		if t.PkgPath() != modelsPkg {
+			return openapi3.NewSchemaRef("", b.structSchema(t))
+		}
+		if _, ok := b.doc.Components.Schemas[t.Name()]; !ok {
+			// Register before recursing so self-references resolve.
+			b.doc.Components.Schemas[t.Name()] = openapi3.NewSchemaRef("", nil)
+			b.doc.Components.Schemas[t.Name()].Value = b.structSchema(t)
+		}
+		return openapi3.NewSchemaRef("#/components/schemas/"+t.Name(), nil)
+	}
+	panic("openapi: no schema for " + t.String())
+}
+
+// structSchema lists t's JSON fields. Fields without omitempty are always
+// present, so they are required.
+func (b *builder) structSchema(t reflect.Type) *openapi3.Schema {
+	s := openapi3.NewObjectSchema()
+	b.addFields(s, t)
+	return s
+}
+
+func (b *builder) addFields(s *openapi3.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			if f.Type.Kind() == reflect.Struct {
+				b.addFields(s, f.Type)
+			}
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := b.schemaOf(f.Type)
+		if values, ok := enums[t.Name()+"."+name]; ok {
+			prop.Value.Enum = values
+		}
+		s.WithPropertyRef(name, prop)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Package openapi describes Hand's HTTP API as an OpenAPI 3 document,
+// generated from the route table in routes.go and the models' JSON
+// encoding, and provides a middleware that checks traffic against it.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+var ginParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+// currentVersion prefixes the API routes. Their unversioned paths are
+// documented as deprecated aliases.
+const currentVersion = "/v1"
+
+// specPath converts a gin route path to an OpenAPI one.
+func specPath(path string) string {
+	return ginParam.ReplaceAllString(path, "{$1}")
+}
+
+var (
+	buildOnce sync.Once
+	doc       *openapi3.T
+	docJSON   []byte
+)
+
+// Document returns the API description. It is built once and must not be
+// modified.
+func Document() *openapi3.T {
+	buildOnce.Do(func() {
+		doc = build()
+		// Fill in $ref targets so the validator can follow them.
+		if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+			panic("openapi: " + err.Error())
+		}
+		var err error
+		if docJSON, err = json.Marshal(doc); err != nil {
+			panic("openapi: " + err.Error())
+		}
+	})
+	return doc
+}
+
+// Serve responds with the document as JSON.
+func Serve(c *gin.Context) {
+	Document()
+	c.Data(http.StatusOK, "application/json; charset=utf-8", docJSON)
+}
+
+// Covers reports routes registered on the router that the document
+// doesn't describe, so a new route can't ship undocumented.
+func Covers(registered gin.RoutesInfo) error {
+	d := Document()
+	var missing []string
+	for _, r := range registered {
+		item := d.Paths.Value(specPath(r.Path))
+		if item == nil || item.GetOperation(r.Method) == nil {
+			missing = append(missing, r.Method+" "+r.Path)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("openapi: undocumented routes: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+type builder struct {
+	doc *openapi3.T
+}
+
+func build() *openapi3.T {
+	b := &builder{doc: &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Hand",
+			Description: "Party service for BananaLabs games. Error bodies carry a machine-readable `error` code and a human-readable `message`.",
+			Version:     "1",
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"player": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme().
+					WithDescription("JWT signed with the tenant's keys")},
+				"service": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+					WithType("apiKey").WithIn("header").WithName("X-Service-Token").
+					WithDescription("A tenant's service credential")},
+			},
+		},
+	}}
+	for _, r := range routes {
+		if r.unversioned {
+			b.addRoute(r, r.path, false)
+			continue
+		}
+		b.addRoute(r, currentVersion+r.path, false)
+		b.addRoute(r, r.path, true)
+	}
+	return b.doc
+}
+
+// addRoute documents r at path. An alias is the deprecated unversioned
+// path of a versioned route.
+func (b *builder) addRoute(r route, path string, alias bool) {
+	op := openapi3.NewOperation()
+	op.Summary = r.summary
+	op.OperationID = operationID(r.method, path)
+	op.Responses = openapi3.NewResponses()
+	op.Responses.Delete("default")
+
+	var notes []string
+	if alias {
+		op.Deprecated = true
+		notes = append(notes, "Deprecated alias of `"+specPath(currentVersion+r.path)+"`, with the same behavior.")
+	}
+
+	for _, name := range ginParam.FindAllStringSubmatch(path, -1) {
+		op.AddParameter(openapi3.NewPathParameter(name[1]).WithSchema(openapi3.NewUUIDSchema()))
+	}
+	for _, q := range r.query {
+		op.AddParameter(openapi3.NewQueryParameter(q.name).WithDescription(q.description).WithSchema(openapi3.NewStringSchema()))
+	}
+
+	errors := r.errors
+	switch r.access {
+	case player:
+		op.Security = openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate("player"))
+		op.AddParameter(openapi3.NewHeaderParameter("X-Game").WithDescription("Game namespace, unless the token names one").WithSchema(openapi3.NewStringSchema()))
+		if !hasQuery(r, "game") {
+			op.AddParameter(openapi3.NewQueryParameter("game").WithDescription("Game namespace, as an alternative to X-Game").WithSchema(openapi3.NewStringSchema()))
+		}
+		errors = join(errors,
+			errs(http.StatusBadRequest, "invalid_game"),
+			errs(http.StatusForbidden, "game_mismatch"),
+			errs(http.StatusTooManyRequests, "rate_limited"))
+	case service:
+		op.Security = openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate("service"))
+		notes = append(notes, "Requires the "+r.scope+" scope.")
+		errors = join(errors, errs(http.StatusForbidden, "insufficient_scope"))
+	}
+
+	op.Description = strings.Join(notes, " ")
+
+	if r.body != nil {
+		body := openapi3.NewRequestBody().WithJSONSchemaRef(b.schemaOf(reflect.TypeOf(r.body)))
+		body.Required = !r.optionalBody
+		op.RequestBody = &openapi3.RequestBodyRef{Value: body}
+	}
+
+	success := openapi3.NewResponse().WithDescription(http.StatusText(r.status))
+	if r.response != nil {
+		schema := b.schemaOf(reflect.TypeOf(r.response))
+		if r.alternate != nil {
+			schema = openapi3.NewSchemaRef("", &openapi3.Schema{OneOf: openapi3.SchemaRefs{schema, b.schemaOf(reflect.TypeOf(r.alternate))}})
+		}
+		success.WithJSONSchemaRef(schema)
+	}
+	op.AddResponse(r.status, success)
+
+	if r.access != public {
+		op.AddResponse(http.StatusUnauthorized, openapi3.NewResponse().
+			WithDescription("Missing, invalid or expired credentials. The `error` is a description rather than a code.").
+			WithJSONSchemaRef(errorSchema(nil)))
+	}
+	for _, status := range statuses(errors) {
+		var codes []string
+		for _, e := range errors {
+			if e.status == status {
+				codes = append(codes, e.code)
+			}
+		}
+		resp := openapi3.NewResponse().
+			WithDescription(http.StatusText(status) + ": `" + strings.Join(codes, "`, `") + "`").
+			WithJSONSchemaRef(errorSchema(codes))
+		if status == http.StatusTooManyRequests {
+			resp.Headers = openapi3.Headers{"Retry-After": &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{
+				Description: "Seconds until the request may be retried",
+				Schema:      openapi3.NewIntegerSchema().NewRef(),
+			}}}}
+		}
+		op.AddResponse(status, resp)
+	}
+
+	b.doc.AddOperation(specPath(path), r.method, op)
+}
+
+// errorSchema is Potassium's ErrorResponse with error limited to codes.
+func errorSchema(codes []string) *openapi3.SchemaRef {
+	s := openapi3.NewObjectSchema().
+		WithProperty("error", openapi3.NewStringSchema()).
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithRequired([]string{"error"})
+	if len(codes) > 0 {
+		values := make([]any, len(codes))
+		for i, code := range codes {
+			values[i] = code
+		}
+		s.Properties["error"].Value.Enum = values
+	}
+	return s.NewRef()
+}
+
+func statuses(errors []apiError) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, e := range errors {
+		if !seen[e.status] {
+			seen[e.status] = true
+			out = append(out, e.status)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func hasQuery(r route, name string) bool {
+	for _, q := range r.query {
+		if q.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// operationID derives a stable ID such as "post_v1_parties_join" or
+// "get_internal_parties_partyId".
+func operationID(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		seg = strings.TrimPrefix(seg, ":")
+		seg = strings.NewReplacer(".", "_", "-", "_").Replace(seg)
+		if seg != "" {
+			parts = append(parts, seg)
+		}
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// Validator checks each request and response against the document and
+// passes any mismatch to report. It is meant for tests, where report can
+// fail the test: every response must be documented, including its status
+// and error code, and a request the document calls invalid must not
+// succeed. Requests to undocumented routes and WebSocket upgrades are
+// passed through unchecked.
+func Validator(report func(c *gin.Context, err error)) gin.HandlerFunc {
+	d := Document()
+	return func(c *gin.Context) {
+		item := d.Paths.Value(specPath(c.FullPath()))
+		if item == nil || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+		op := item.GetOperation(c.Request.Method)
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request.Clone(context.Background()),
+			PathParams: params,
+			Route:      &routers.Route{Spec: d, Path: specPath(c.FullPath()), PathItem: item, Method: c.Request.Method, Operation: op},
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				report(c, fmt.Errorf("read request body: %w", err))
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			input.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		requestErr := openapi3filter.ValidateRequest(c.Request.Context(), input)
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if requestErr != nil && w.Status() < http.StatusBadRequest {
+			report(c, fmt.Errorf("%s %s succeeded with a request the document rejects: %w", c.Request.Method, c.FullPath(), requestErr))
+		}
+		err := openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 w.Status(),
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(w.body.Bytes())),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+		})
+		if err != nil {
+			report(c, fmt.Errorf("%s %s -> %d does not match the document: %w", c.Request.Method, c.FullPath(), w.Status(), err))
+		}
+	}
+}
+
+// recorder keeps a copy of the response body for validation.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
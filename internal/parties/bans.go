@@ -0,0 +1,128 @@
+package parties
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var errBanned = newOpError(http.StatusForbidden, "banned", "You are banned from that party")
+
+// ListBans returns the active bans on the caller's party.
+func (h *Handler) ListBans(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, err := h.authorizeMember(ctx, member, PermBan); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	bans := make([]models.PartyBan, 0)
+	err = h.db.NewSelect().
+		Model(&bans).
+		Where("party_id = ?", member.PartyID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		OrderExpr("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch bans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+func (h *Handler) LiftBan(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid account ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, err := h.authorizeMember(ctx, member, PermBan); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	res, err := h.db.NewDelete().
+		Model((*models.PartyBan)(nil)).
+		Where("party_id = ? AND account_id = ?", member.PartyID, targetID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "unban_failed",
+			Message: "Failed to lift ban",
+		})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_banned",
+			Message: "That player is not banned",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ban lifted"})
+}
+
+// checkBan rejects accountID if it holds an active ban on the party.
+func (h *Handler) checkBan(ctx context.Context, partyID, accountID uuid.UUID) *opError {
+	banned, err := h.db.NewSelect().
+		Model((*models.PartyBan)(nil)).
+		Where("party_id = ? AND account_id = ?", partyID, accountID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		Exists(ctx)
+	if err != nil {
+		return newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to check bans")
+	}
+	if banned {
+		return errBanned
+	}
+	return nil
+}
+
+// upsertBan records a ban, replacing any earlier ban of the same player.
+func upsertBan(ctx context.Context, tx bun.Tx, ban *models.PartyBan) error {
+	_, err := tx.NewInsert().
+		Model(ban).
+		On("CONFLICT (party_id, account_id) DO UPDATE").
+		Set("banned_by = EXCLUDED.banned_by").
+		Set("expires_at = EXCLUDED.expires_at").
+		Set("created_at = EXCLUDED.created_at").
+		Exec(ctx)
+	return err
+}
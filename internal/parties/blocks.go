@@ -0,0 +1,190 @@
+package parties
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var errBlocked = newOpError(http.StatusForbidden, "blocked", "A block between you and a member of that party prevents this")
+
+func (h *Handler) ListBlocks(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	blocks := make([]models.Block, 0)
+	err := h.db.NewSelect().
+		Model(&blocks).
+		Where("tenant = ? AND account_id = ?", getTenant(c), accountID).
+		OrderExpr("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch blocks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocks": blocks})
+}
+
+func (h *Handler) BlockAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AccountID uuid.UUID `json:"account_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "account_id is required",
+		})
+		return
+	}
+	if req.AccountID == accountID {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "You can't block yourself",
+		})
+		return
+	}
+
+	count, err := h.db.NewSelect().
+		Model((*models.Block)(nil)).
+		Where("tenant = ? AND account_id = ?", getTenant(c), accountID).
+		Count(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "block_failed",
+			Message: "Failed to block player",
+		})
+		return
+	}
+	if count >= models.MaxBlocks {
+		c.JSON(http.StatusConflict, middleware.ErrorResponse{
+			Error:   "too_many_blocks",
+			Message: "You can block at most " + strconv.Itoa(models.MaxBlocks) + " players",
+		})
+		return
+	}
+
+	block := &models.Block{
+		Tenant:    getTenant(c),
+		AccountID: accountID,
+		BlockedID: req.AccountID,
+		CreatedAt: time.Now().UTC(),
+	}
+	_, err = h.db.NewInsert().
+		Model(block).
+		On("CONFLICT (tenant, account_id, blocked_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "block_failed",
+			Message: "Failed to block player",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Player blocked"})
+}
+
+func (h *Handler) UnblockAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	blockedID, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid account ID",
+		})
+		return
+	}
+
+	res, err := h.db.NewDelete().
+		Model((*models.Block)(nil)).
+		Where("tenant = ? AND account_id = ? AND blocked_id = ?", getTenant(c), accountID, blockedID).
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "unblock_failed",
+			Message: "Failed to unblock player",
+		})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_blocked",
+			Message: "That player is not blocked",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Player unblocked"})
+}
+
+// checkBlocks rejects accountID if it and any of others have blocked one
+// another. Blocks stored by Hand are checked first, then the configured
+// provider; a provider that can't be reached doesn't stop the action.
+func (h *Handler) checkBlocks(ctx context.Context, tenant string, accountID uuid.UUID, others []uuid.UUID) *opError {
+	if len(others) == 0 {
+		return nil
+	}
+
+	n, err := h.db.NewSelect().
+		Model((*models.Block)(nil)).
+		Where("tenant = ?", tenant).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("(account_id = ? AND blocked_id IN (?))", accountID, bun.In(others)).
+				WhereOr("(blocked_id = ? AND account_id IN (?))", accountID, bun.In(others))
+		}).
+		Count(ctx)
+	if err != nil {
+		return newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to check blocks")
+	}
+	if n > 0 {
+		return errBlocked
+	}
+
+	if h.cfg.Blocks == nil {
+		return nil
+	}
+	blocked, err := h.cfg.Blocks.Blocked(ctx, tenant, accountID, others)
+	if err != nil {
+		log.Printf("Block provider check for %s failed: %v", accountID, err)
+		return nil
+	}
+	if blocked {
+		return errBlocked
+	}
+	return nil
+}
+
+func memberIDs(party *models.Party) []uuid.UUID {
+	ids := make([]uuid.UUID, len(party.Members))
+	for i, m := range party.Members {
+		ids[i] = m.AccountID
+	}
+	return ids
+}
@@ -0,0 +1,317 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+
+	// chatSweepInterval is how often messages past ChatRetention.MaxAge
+	// are deleted from every party, including archived ones that never
+	// see another post.
+	chatSweepInterval = 10 * time.Minute
+)
+
+// ChatRetention bounds how much history is kept per party. Zero values
+// disable the corresponding bound.
+type ChatRetention struct {
+	MaxMessages int
+	MaxAge      time.Duration
+}
+
+var DefaultChatRetention = ChatRetention{MaxMessages: 200, MaxAge: 24 * time.Hour}
+
+func (h *Handler) PostMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "body is required",
+		})
+		return
+	}
+
+	msg, err := h.postMessage(ctx, getScope(c), accountID, req.Body)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, msg)
+}
+
+func (h *Handler) postMessage(ctx context.Context, sc scope, accountID uuid.UUID, body string) (*models.PartyMessage, *opError) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > models.MaxMessageLength {
+		return nil, newOpError(http.StatusBadRequest, "invalid_message",
+			"Message must be between 1 and "+strconv.Itoa(models.MaxMessageLength)+" characters")
+	}
+
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		return nil, errNotInParty
+	}
+
+	msg := &models.PartyMessage{
+		ID:        uuid.New(),
+		PartyID:   member.PartyID,
+		AccountID: accountID,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(msg).Exec(ctx); err != nil {
+			return err
+		}
+		return h.pruneMessages(ctx, tx, member.PartyID)
+	})
+	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "post_failed", "Failed to post message")
+	}
+
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.ChatMessage, PartyID: member.PartyID, AccountID: accountID, ActorID: accountID, Data: msg})
+	return msg, nil
+}
+
+// GetMessages returns history newest first. Pass the returned next_cursor
+// as ?before= to fetch the page after it.
+func (h *Handler) GetMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "limit must be between 1 and " + strconv.Itoa(maxHistoryLimit),
+			})
+			return
+		}
+		limit = n
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_in_party",
+			Message: "You are not in a party",
+		})
+		return
+	}
+
+	q := h.db.NewSelect().
+		Model((*models.PartyMessage)(nil)).
+		Where("party_id = ?", member.PartyID)
+	// Pruning only runs on the next post, so hide anything already past
+	// the age bound here.
+	if maxAge := h.cfg.ChatRetention.MaxAge; maxAge > 0 {
+		q = q.Where("created_at >= ?", time.Now().UTC().Add(-maxAge))
+	}
+
+	if raw := c.Query("before"); raw != "" {
+		cursor := new(models.PartyMessage)
+		cursorID, err := uuid.Parse(raw)
+		if err == nil {
+			err = h.db.NewSelect().
+				Model(cursor).
+				Where("id = ? AND party_id = ?", cursorID, member.PartyID).
+				Scan(ctx)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "Invalid or expired cursor",
+			})
+			return
+		}
+		q = q.Where("(created_at < ? OR (created_at = ? AND id < ?))", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	messages := make([]models.PartyMessage, 0, limit)
+	err = q.OrderExpr("created_at DESC, id DESC").
+		Limit(limit).
+		Scan(ctx, &messages)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch messages",
+		})
+		return
+	}
+
+	resp := gin.H{"messages": messages}
+	if len(messages) == limit {
+		resp["next_cursor"] = messages[len(messages)-1].ID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid message ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_in_party",
+			Message: "You are not in a party",
+		})
+		return
+	}
+
+	msg := new(models.PartyMessage)
+	err = h.db.NewSelect().
+		Model(msg).
+		Where("id = ? AND party_id = ?", messageID, member.PartyID).
+		Scan(ctx)
+	if err != nil {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_found",
+			Message: "Message not found",
+		})
+		return
+	}
+
+	if msg.AccountID != accountID {
+		party, err := h.getParty(ctx, member.PartyID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+				Error:   "fetch_failed",
+				Message: "Failed to fetch party",
+			})
+			return
+		}
+		// Authors who have since left are treated as plain members.
+		author, err := h.findMember(ctx, member.PartyID, msg.AccountID)
+		if err != nil {
+			author = &models.PartyMember{Role: models.RoleMember}
+		}
+		if err := authorizeOver(party, member, author, PermModerateChat); err != nil {
+			writeError(c, err)
+			return
+		}
+	}
+
+	_, err = h.db.NewDelete().
+		Model((*models.PartyMessage)(nil)).
+		Where("id = ?", msg.ID).
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "delete_failed",
+			Message: "Failed to delete message",
+		})
+		return
+	}
+
+	h.publish(events.Event{
+		Tenant:    member.Tenant,
+		Type:      events.ChatDeleted,
+		PartyID:   member.PartyID,
+		AccountID: msg.AccountID,
+		ActorID:   accountID,
+		Data:      gin.H{"message_id": msg.ID},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Message deleted"})
+}
+
+// pruneMessages enforces the chat retention bounds for a party.
+func (h *Handler) pruneMessages(ctx context.Context, tx bun.Tx, partyID uuid.UUID) error {
+	retention := h.cfg.ChatRetention
+
+	if retention.MaxAge > 0 {
+		_, err := tx.NewDelete().
+			Model((*models.PartyMessage)(nil)).
+			Where("party_id = ? AND created_at < ?", partyID, time.Now().UTC().Add(-retention.MaxAge)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if retention.MaxMessages > 0 {
+		keep := tx.NewSelect().
+			Model((*models.PartyMessage)(nil)).
+			Column("id").
+			Where("party_id = ?", partyID).
+			OrderExpr("created_at DESC, id DESC").
+			Limit(retention.MaxMessages)
+		_, err := tx.NewDelete().
+			Model((*models.PartyMessage)(nil)).
+			Where("party_id = ? AND id NOT IN (?)", partyID, keep).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunChatSweeper deletes messages older than the retention age from every
+// party until ctx is done.
+func (h *Handler) RunChatSweeper(ctx context.Context) {
+	if h.cfg.ChatRetention.MaxAge <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(chatSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := h.db.NewDelete().
+				Model((*models.PartyMessage)(nil)).
+				Where("created_at < ?", time.Now().UTC().Add(-h.cfg.ChatRetention.MaxAge)).
+				Exec(ctx)
+			if err != nil {
+				log.Printf("Chat sweep failed: %v", err)
+			}
+		}
+	}
+}
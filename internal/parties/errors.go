@@ -0,0 +1,30 @@
+package parties
+
+import (
+	"net/http"
+
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// opError is a failed party operation. Operations shared by the REST
+// handlers and the WebSocket gateway return it so both report the same
+// status and error code.
+type opError struct {
+	status int
+	resp   middleware.ErrorResponse
+}
+
+func newOpError(status int, code, message string) *opError {
+	return &opError{status: status, resp: middleware.ErrorResponse{Error: code, Message: message}}
+}
+
+func (e *opError) Error() string {
+	return e.resp.Error
+}
+
+func writeError(c *gin.Context, err *opError) {
+	c.JSON(err.status, err.resp)
+}
+
+var errNotInParty = newOpError(http.StatusNotFound, "not_in_party", "You are not in a party")
@@ -0,0 +1,189 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// lastActivityExpr is when a party was last touched: its own updated_at or
+// the most recent heartbeat or join of any member, whichever is later.
+const lastActivityExpr = `MAX(p.updated_at, COALESCE(
+	(SELECT MAX(COALESCE(pm.last_seen_at, pm.joined_at)) FROM party_members AS pm WHERE pm.party_id = p.id),
+	p.updated_at))`
+
+// ExpiryConfig controls disbanding of idle parties. A party with no
+// activity for IdleAfter is disbanded; members are warned WarnBefore that.
+// Archived parties are deleted ArchiveRetention after they were archived.
+// A zero IdleAfter disables expiry, a zero WarnBefore disables warnings and
+// a zero ArchiveRetention keeps archived parties forever.
+type ExpiryConfig struct {
+	IdleAfter        time.Duration
+	WarnBefore       time.Duration
+	ArchiveRetention time.Duration
+	Interval         time.Duration
+}
+
+var DefaultExpiryConfig = ExpiryConfig{
+	IdleAfter:        24 * time.Hour,
+	WarnBefore:       15 * time.Minute,
+	ArchiveRetention: 7 * 24 * time.Hour,
+	Interval:         time.Minute,
+}
+
+// errNoLongerIdle stops a disband whose party saw activity after the scan.
+var errNoLongerIdle = errors.New("party active again")
+
+// RunExpiryScheduler disbands idle parties and deletes old archived ones
+// until ctx is done.
+func (h *Handler) RunExpiryScheduler(ctx context.Context) {
+	cfg := h.cfg.Expiry
+	if (cfg.IdleAfter <= 0 && cfg.ArchiveRetention <= 0) || cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	// warned remembers the activity time each party was warned about, so a
+	// party is warned once per idle stretch rather than on every tick.
+	warned := make(map[uuid.UUID]time.Time)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if cfg.IdleAfter > 0 {
+				if err := h.expireIdleParties(ctx, warned); err != nil {
+					log.Printf("Party expiry failed: %v", err)
+				}
+			}
+			if cfg.ArchiveRetention > 0 {
+				if err := h.deleteArchivedParties(ctx); err != nil {
+					log.Printf("Archived party cleanup failed: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func (h *Handler) expireIdleParties(ctx context.Context, warned map[uuid.UUID]time.Time) error {
+	cfg := h.cfg.Expiry
+	now := time.Now().UTC()
+	expireCutoff := now.Add(-cfg.IdleAfter)
+	warnCutoff := expireCutoff
+	if cfg.WarnBefore > 0 && cfg.WarnBefore < cfg.IdleAfter {
+		warnCutoff = expireCutoff.Add(cfg.WarnBefore)
+	}
+
+	var idle []struct {
+		ID           uuid.UUID `bun:"id"`
+		OwnerID      uuid.UUID `bun:"owner_id"`
+		Tenant       string    `bun:"tenant"`
+		LastActiveAt time.Time `bun:"last_active_at"`
+	}
+	err := h.db.NewSelect().
+		TableExpr("parties AS p").
+		Column("p.id", "p.owner_id", "p.tenant").
+		ColumnExpr(lastActivityExpr+" AS last_active_at").
+		Where(lastActivityExpr+" < ?", warnCutoff).
+		Where("p.archived_at IS NULL").
+		Scan(ctx, &idle)
+	if err != nil {
+		return err
+	}
+
+	stillIdle := make(map[uuid.UUID]struct{}, len(idle))
+	for _, p := range idle {
+		if p.LastActiveAt.Before(expireCutoff) {
+			err := h.disbandIdle(ctx, p.ID, expireCutoff)
+			if errors.Is(err, errNoLongerIdle) {
+				delete(warned, p.ID)
+				continue
+			}
+			if err != nil {
+				log.Printf("Failed to disband idle party %s: %v", p.ID, err)
+				continue
+			}
+			h.publishDisbanded(p.Tenant, p.ID, p.OwnerID, DisbandIdle)
+			log.Printf("Disbanded party %s: idle since %s", p.ID, p.LastActiveAt.Format(time.RFC3339))
+			delete(warned, p.ID)
+			continue
+		}
+
+		stillIdle[p.ID] = struct{}{}
+		if last, ok := warned[p.ID]; ok && last.Equal(p.LastActiveAt) {
+			continue
+		}
+		warned[p.ID] = p.LastActiveAt
+		h.publish(events.Event{
+			Tenant:    p.Tenant,
+			Type:      events.PartyExpiring,
+			PartyID:   p.ID,
+			AccountID: p.OwnerID,
+			ActorID:   p.OwnerID,
+			Data:      gin.H{"expires_at": p.LastActiveAt.Add(cfg.IdleAfter)},
+		})
+	}
+
+	for id := range warned {
+		if _, ok := stillIdle[id]; !ok {
+			delete(warned, id)
+		}
+	}
+	return nil
+}
+
+// disbandIdle deletes an idle party, checking again in the same
+// transaction that it has had no activity since expireCutoff.
+func (h *Handler) disbandIdle(ctx context.Context, partyID uuid.UUID, expireCutoff time.Time) error {
+	return h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		idle, err := tx.NewSelect().
+			TableExpr("parties AS p").
+			Where("p.id = ? AND p.archived_at IS NULL", partyID).
+			Where(lastActivityExpr+" < ?", expireCutoff).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !idle {
+			return errNoLongerIdle
+		}
+		return deleteParty(ctx, tx, partyID)
+	})
+}
+
+// deleteArchivedParties removes merged and split parties, with their chat
+// history and bans, once they have been archived for ArchiveRetention.
+func (h *Handler) deleteArchivedParties(ctx context.Context) error {
+	var ids []uuid.UUID
+	err := h.db.NewSelect().
+		Model((*models.Party)(nil)).
+		Column("id").
+		Where("archived_at < ?", time.Now().UTC().Add(-h.cfg.Expiry.ArchiveRetention)).
+		Scan(ctx, &ids)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+			return deleteParty(ctx, tx, id)
+		})
+		if err != nil {
+			log.Printf("Failed to delete archived party %s: %v", id, err)
+			continue
+		}
+		log.Printf("Deleted archived party %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package parties
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultGame is the namespace used when neither the token nor the request
+// names one.
+const DefaultGame = "default"
+
+var gamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// GameConfig holds settings for one game namespace.
+type GameConfig struct {
+	// MaxSize is the size of new parties. Zero uses models.DefaultMaxSize.
+	MaxSize int
+	// SpectatorSlots is the spectator capacity of new parties. Zero uses
+	// models.DefaultSpectatorSlots; a negative value allows no spectators.
+	SpectatorSlots int
+	// Modes lists the values allowed for the party metadata key "mode".
+	// Empty allows any value.
+	Modes []string
+}
+
+// ParseGames parses namespace settings written as
+// "<game>=<max size>[+<spectators>][:<mode>|<mode>...]", comma-separated,
+// e.g. "arena=4+2:duel|squad,kart=12".
+func ParseGames(s string) (map[string]GameConfig, error) {
+	games := make(map[string]GameConfig)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !gamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid game entry %q, expected <game>=<max size>[+<spectators>][:<modes>]", entry)
+		}
+		size, modes, _ := strings.Cut(spec, ":")
+		size, spectators, hasSpectators := strings.Cut(size, "+")
+		var cfg GameConfig
+		if hasSpectators {
+			n, err := strconv.Atoi(strings.TrimSpace(spectators))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid spectator slots in game entry %q", entry)
+			}
+			cfg.SpectatorSlots = n
+			if n == 0 {
+				cfg.SpectatorSlots = -1
+			}
+		}
+		if size = strings.TrimSpace(size); size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil || n < 2 {
+				return nil, fmt.Errorf("invalid max size in game entry %q", entry)
+			}
+			cfg.MaxSize = n
+		}
+		for _, mode := range strings.Split(modes, "|") {
+			if mode = strings.TrimSpace(mode); mode != "" {
+				cfg.Modes = append(cfg.Modes, mode)
+			}
+		}
+		games[name] = cfg
+	}
+	return games, nil
+}
+
+func (h *Handler) gameConfig(game string) GameConfig {
+	cfg := h.cfg.Games[game]
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = models.DefaultMaxSize
+	}
+	switch {
+	case cfg.SpectatorSlots == 0:
+		cfg.SpectatorSlots = models.DefaultSpectatorSlots
+	case cfg.SpectatorSlots < 0:
+		cfg.SpectatorSlots = 0
+	}
+	return cfg
+}
+
+// allowsMode reports whether mode is a valid "mode" metadata value.
+func (g GameConfig) allowsMode(mode any) bool {
+	if len(g.Modes) == 0 {
+		return true
+	}
+	s, ok := mode.(string)
+	if !ok {
+		return false
+	}
+	for _, m := range g.Modes {
+		if m == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveGame picks the game namespace for a player request. A game claim
+// in the token wins; otherwise the X-Game header or ?game= is used, falling
+// back to DefaultGame. When namespaces are configured only those (and the
+// default) are accepted.
+func (h *Handler) ResolveGame(c *gin.Context) {
+	requested := c.GetHeader("X-Game")
+	if requested == "" {
+		requested = c.Query("game")
+	}
+
+	game := c.GetString(auth.GameKey)
+	switch {
+	case game != "" && requested != "" && requested != game:
+		c.AbortWithStatusJSON(http.StatusForbidden, middleware.ErrorResponse{
+			Error:   "game_mismatch",
+			Message: "Your token is not valid for that game",
+		})
+		return
+	case game == "" && requested != "":
+		game = requested
+	case game == "":
+		game = DefaultGame
+	}
+
+	_, known := h.cfg.Games[game]
+	if !gamePattern.MatchString(game) || (len(h.cfg.Games) > 0 && !known && game != DefaultGame) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_game",
+			Message: "Unknown game",
+		})
+		return
+	}
+
+	c.Set("game", game)
+	c.Next()
+}
+
+func getGame(c *gin.Context) string {
+	if game := c.GetString("game"); game != "" {
+		return game
+	}
+	return DefaultGame
+}
@@ -0,0 +1,360 @@
+package parties
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxSocketMessageSize = 4096
+	socketWriteTimeout   = 5 * time.Second
+	socketPingInterval   = 30 * time.Second
+	// socketPongTimeout must exceed socketPingInterval so a healthy client
+	// always answers a ping before its read deadline passes.
+	socketPongTimeout = socketPingInterval + 10*time.Second
+)
+
+// GatewayConfig bounds the WebSocket gateway. Zero values disable the
+// corresponding bound.
+type GatewayConfig struct {
+	MaxConnections           int
+	MaxConnectionsPerAccount int
+	// MessageLimit applies to commands sent on a single connection.
+	MessageLimit ratelimit.Limit
+	// AllowedOrigins lists extra browser origins allowed to connect, e.g.
+	// "https://launcher.example.com". Same-origin requests and clients that
+	// send no Origin are always allowed.
+	AllowedOrigins []string
+}
+
+var DefaultGatewayConfig = GatewayConfig{
+	MaxConnections:           10000,
+	MaxConnectionsPerAccount: 3,
+	MessageLimit:             ratelimit.Limit{Burst: 20, Period: 10 * time.Second},
+}
+
+// gateway tracks open sockets so connection counts stay bounded.
+type gateway struct {
+	cfg        GatewayConfig
+	upgrader   websocket.Upgrader
+	mu         sync.Mutex
+	total      int
+	perAccount map[string]int
+}
+
+// TokenSubprotocol lets browsers, which can't set headers on the upgrade
+// request, pass the JWT as the WebSocket subprotocol that follows it:
+// new WebSocket(url, ["bearer", jwt]). The server selects "bearer", so the
+// token is never echoed back.
+const TokenSubprotocol = "bearer"
+
+func newGateway(cfg GatewayConfig) *gateway {
+	g := &gateway{cfg: cfg, perAccount: make(map[string]int)}
+	g.upgrader = websocket.Upgrader{CheckOrigin: g.checkOrigin, Subprotocols: []string{TokenSubprotocol}}
+	return g
+}
+
+func (g *gateway) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "http://"+r.Host || origin == "https://"+r.Host {
+		return true
+	}
+	for _, allowed := range g.cfg.AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire and release count sockets per tenant and account, as account IDs
+// are only unique within a tenant.
+func (g *gateway) acquire(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cfg.MaxConnections > 0 && g.total >= g.cfg.MaxConnections {
+		return false
+	}
+	if g.cfg.MaxConnectionsPerAccount > 0 && g.perAccount[key] >= g.cfg.MaxConnectionsPerAccount {
+		return false
+	}
+	g.total++
+	g.perAccount[key]++
+	return true
+}
+
+func (g *gateway) release(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.total--
+	if g.perAccount[key]--; g.perAccount[key] <= 0 {
+		delete(g.perAccount, key)
+	}
+}
+
+// socketCommand is a client-to-server frame.
+type socketCommand struct {
+	ID   string          `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// socketFrame is a server-to-client frame. Type is "party" (a snapshot of
+// the caller's party, null when not in one), "event", "result" or "error".
+type socketFrame struct {
+	Type  string                    `json:"type"`
+	ID    string                    `json:"id,omitempty"`
+	Event *events.Event             `json:"event,omitempty"`
+	Data  any                       `json:"data,omitempty"`
+	Error *middleware.ErrorResponse `json:"error,omitempty"`
+}
+
+// Gateway upgrades to a WebSocket that streams party events and accepts
+// ready, chat and kick commands.
+func (h *Handler) Gateway(c *gin.Context) {
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	if h.cfg.Events == nil {
+		c.JSON(http.StatusServiceUnavailable, middleware.ErrorResponse{
+			Error:   "gateway_disabled",
+			Message: "Live updates are not enabled",
+		})
+		return
+	}
+
+	sc := getScope(c)
+	key := sc.tenant + ":" + accountID.String()
+	if !h.gateway.acquire(key) {
+		c.JSON(http.StatusServiceUnavailable, middleware.ErrorResponse{
+			Error:   "too_many_connections",
+			Message: "Too many open connections",
+		})
+		return
+	}
+	defer h.gateway.release(key)
+
+	conn, err := h.gateway.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written the handshake error.
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxSocketMessageSize)
+
+	s := &socket{
+		h:         h,
+		conn:      conn,
+		accountID: accountID,
+		scope:     sc,
+		limiter:   ratelimit.NewMemoryStore(),
+	}
+	s.serve(c.Request.Context())
+}
+
+type socket struct {
+	h         *Handler
+	conn      *websocket.Conn
+	accountID uuid.UUID
+	scope     scope
+	sub       *events.Subscription
+	limiter   *ratelimit.MemoryStore
+}
+
+func (s *socket) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before the first snapshot so no change slips in between.
+	s.sub = s.h.cfg.Events.Subscribe(events.AccountTopic(s.scope.tenant, s.accountID))
+	defer s.sub.Close()
+
+	if err := s.resync(ctx); err != nil {
+		return
+	}
+	// An open socket counts as a heartbeat.
+	s.h.heartbeat(ctx, s.scope, s.accountID, "")
+
+	// Reads happen on their own goroutine; all writes stay on this one, as
+	// gorilla/websocket allows one concurrent reader and one writer.
+	_ = s.conn.SetReadDeadline(time.Now().Add(socketPongTimeout))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(socketPongTimeout))
+	})
+
+	commands := make(chan []byte)
+	go func() {
+		defer close(commands)
+		for {
+			_, data, err := s.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case commands <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(socketPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case data, ok := <-commands:
+			if !ok {
+				return
+			}
+			if err := s.handle(ctx, data); err != nil {
+				return
+			}
+
+		case ev, ok := <-s.sub.Events():
+			if !ok {
+				s.close(websocket.CloseTryAgainLater, "event backlog exceeded")
+				return
+			}
+			if err := s.write(ctx, socketFrame{Type: "event", Event: &ev}); err != nil {
+				return
+			}
+			if s.needsResync(ev) {
+				if err := s.resync(ctx); err != nil {
+					return
+				}
+			}
+
+		case <-ping.C:
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteTimeout))
+			if err != nil {
+				return
+			}
+			s.h.heartbeat(ctx, s.scope, s.accountID, "")
+		}
+	}
+}
+
+// needsResync reports whether ev may have moved the socket's account into
+// or out of a party, or changed what its snapshot is allowed to show.
+func (s *socket) needsResync(ev events.Event) bool {
+	switch ev.Type {
+	case events.PartyDisbanded, events.PartyMerged, events.PartySplit, events.PartySettingsChanged, events.PartyInviteChanged:
+		return true
+	case events.PartyCreated, events.MemberJoined, events.MemberLeft, events.MemberKicked,
+		events.MemberRoleChanged, events.PartyOwnerChanged:
+		return ev.AccountID == s.accountID
+	}
+	return false
+}
+
+// resync points the subscription at the account's current party and sends
+// a fresh snapshot of it.
+func (s *socket) resync(ctx context.Context) error {
+	topics := []string{events.AccountTopic(s.scope.tenant, s.accountID)}
+	var party *models.Party
+
+	member, err := s.h.findMembership(ctx, s.scope, s.accountID)
+	if err == nil {
+		topics = append(topics, events.PartyTopic(member.PartyID))
+		party, err = s.h.getPartyWithMembers(ctx, member.PartyID)
+		if err != nil {
+			party = nil
+		} else {
+			party = viewFor(party, s.accountID)
+		}
+	}
+	s.sub.Resubscribe(topics...)
+
+	return s.write(ctx, socketFrame{Type: "party", Data: party})
+}
+
+func (s *socket) handle(ctx context.Context, data []byte) error {
+	var cmd socketCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return s.writeError(ctx, "", newOpError(http.StatusBadRequest, "invalid_request", "Malformed command"))
+	}
+
+	if limit := s.h.cfg.Gateway.MessageLimit; limit.Enabled() {
+		if res, _ := s.limiter.Take(ctx, "commands", limit); !res.Allowed {
+			return s.writeError(ctx, cmd.ID, newOpError(http.StatusTooManyRequests, "rate_limited", "Too many commands. Slow down."))
+		}
+	}
+
+	result, opErr := s.dispatch(ctx, cmd)
+	if opErr != nil {
+		return s.writeError(ctx, cmd.ID, opErr)
+	}
+	return s.write(ctx, socketFrame{Type: "result", ID: cmd.ID, Data: result})
+}
+
+func (s *socket) dispatch(ctx context.Context, cmd socketCommand) (any, *opError) {
+	switch cmd.Type {
+	case "ready":
+		var data struct {
+			Ready *bool `json:"ready"`
+		}
+		if err := json.Unmarshal(cmd.Data, &data); err != nil || data.Ready == nil {
+			return nil, newOpError(http.StatusBadRequest, "invalid_request", "ready is required")
+		}
+		if err := s.h.setReady(ctx, s.scope, s.accountID, *data.Ready); err != nil {
+			return nil, err
+		}
+		return gin.H{"ready": *data.Ready}, nil
+
+	case "chat":
+		var data struct {
+			Body string `json:"body"`
+		}
+		if err := json.Unmarshal(cmd.Data, &data); err != nil {
+			return nil, newOpError(http.StatusBadRequest, "invalid_request", "body is required")
+		}
+		return s.h.postMessage(ctx, s.scope, s.accountID, data.Body)
+
+	case "kick":
+		var data kickRequest
+		if err := json.Unmarshal(cmd.Data, &data); err != nil || data.AccountID == uuid.Nil {
+			return nil, newOpError(http.StatusBadRequest, "invalid_request", "account_id is required")
+		}
+		if err := s.h.kickMember(ctx, s.scope, s.accountID, data); err != nil {
+			return nil, err
+		}
+		if data.Ban {
+			return gin.H{"message": "Member kicked and banned"}, nil
+		}
+		return gin.H{"message": "Member kicked"}, nil
+	}
+
+	return nil, newOpError(http.StatusBadRequest, "unknown_command", "Unknown command type")
+}
+
+func (s *socket) write(_ context.Context, frame socketFrame) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	return s.conn.WriteJSON(frame)
+}
+
+func (s *socket) close(code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(socketWriteTimeout))
+}
+
+func (s *socket) writeError(ctx context.Context, id string, err *opError) error {
+	resp := err.resp
+	return s.write(ctx, socketFrame{Type: "error", ID: id, Error: &resp})
+}
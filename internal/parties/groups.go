@@ -0,0 +1,364 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	errGroupNotFound = newOpError(http.StatusNotFound, "group_not_found", "No such sub-group in your party")
+	errGroupFull     = newOpError(http.StatusConflict, "group_full", "Sub-group is full")
+	errIsSpectator   = newOpError(http.StatusConflict, "is_spectator", "Spectators can't join sub-groups")
+)
+
+// CreateGroup adds a named sub-group to the caller's party.
+func (h *Handler) CreateGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name    string `json:"name" binding:"required"`
+		MaxSize int    `json:"max_size" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "name and max_size are required",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	party, opErr := h.authorizeMember(ctx, member, PermManageGroups)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	name, opErr := h.checkText(req.Name, "name", models.MaxGroupNameLength)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	if name == "" {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_name",
+			Message: "The name can't be empty",
+		})
+		return
+	}
+	if req.MaxSize < 1 || req.MaxSize > party.MaxSize {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "max_size must be between 1 and " + strconv.Itoa(party.MaxSize),
+		})
+		return
+	}
+
+	group := &models.PartyGroup{
+		ID:        uuid.New(),
+		PartyID:   party.ID,
+		Name:      name,
+		MaxSize:   req.MaxSize,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		var groups []models.PartyGroup
+		err := tx.NewSelect().Model(&groups).Where("party_id = ?", party.ID).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		if len(groups) >= models.MaxGroups {
+			return newOpError(http.StatusConflict, "too_many_groups", "A party can have at most "+strconv.Itoa(models.MaxGroups)+" sub-groups")
+		}
+		for _, g := range groups {
+			if strings.EqualFold(g.Name, name) {
+				return newOpError(http.StatusConflict, "group_exists", "Your party already has a sub-group with that name")
+			}
+		}
+		_, err = tx.NewInsert().Model(group).Exec(ctx)
+		return err
+	})
+	var txErr *opError
+	if errors.As(err, &txErr) {
+		writeError(c, txErr)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "create_failed",
+			Message: "Failed to create sub-group",
+		})
+		return
+	}
+
+	h.publish(events.Event{Tenant: party.Tenant, Type: events.PartyGroupCreated, PartyID: party.ID, AccountID: accountID, ActorID: accountID, Data: group})
+	c.JSON(http.StatusCreated, group)
+}
+
+// DeleteGroup removes a sub-group. Its members stay in the party without
+// a sub-group.
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid sub-group ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	party, opErr := h.authorizeMember(ctx, member, PermManageGroups)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model((*models.PartyGroup)(nil)).
+			Where("id = ? AND party_id = ?", groupID, party.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errGroupNotFound
+		}
+		_, err = tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("group_id = NULL").
+			Where("party_id = ? AND group_id = ?", party.ID, groupID).
+			Exec(ctx)
+		return err
+	})
+	var txErr *opError
+	if errors.As(err, &txErr) {
+		writeError(c, txErr)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "delete_failed",
+			Message: "Failed to delete sub-group",
+		})
+		return
+	}
+
+	h.publish(events.Event{Tenant: party.Tenant, Type: events.PartyGroupDeleted, PartyID: party.ID, AccountID: accountID, ActorID: accountID, Data: gin.H{"group_id": groupID}})
+	c.JSON(http.StatusOK, gin.H{"message": "Sub-group deleted"})
+}
+
+// JoinGroup moves the caller into a sub-group of their party.
+func (h *Handler) JoinGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid sub-group ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	h.respondGroupChange(c, member, accountID, accountID, &groupID)
+}
+
+// LeaveGroup takes the caller out of their sub-group. They stay in the
+// party.
+func (h *Handler) LeaveGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	h.respondGroupChange(c, member, accountID, accountID, nil)
+}
+
+// AssignGroup puts another member into a sub-group, or takes them out of
+// theirs when group_id is null.
+func (h *Handler) AssignGroup(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AccountID uuid.UUID  `json:"account_id" binding:"required"`
+		GroupID   *uuid.UUID `json:"group_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "account_id is required",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermManageGroups); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	if _, err := h.findMember(ctx, member.PartyID, req.AccountID); err != nil {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_in_party",
+			Message: "That player is not in your party",
+		})
+		return
+	}
+	h.respondGroupChange(c, member, req.AccountID, accountID, req.GroupID)
+}
+
+// respondGroupChange moves accountID into groupID, or out of any sub-group
+// when it is nil, and responds with the party.
+func (h *Handler) respondGroupChange(c *gin.Context, member *models.PartyMember, accountID, actorID uuid.UUID, groupID *uuid.UUID) {
+	ctx := c.Request.Context()
+	party, err := h.getParty(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	if isLocked(party, time.Now()) {
+		writeError(c, errPartyLocked)
+		return
+	}
+
+	if opErr := h.setGroup(ctx, party, accountID, groupID); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	h.publish(events.Event{
+		Tenant:    party.Tenant,
+		Type:      events.MemberGroupChanged,
+		PartyID:   party.ID,
+		AccountID: accountID,
+		ActorID:   actorID,
+		Data:      gin.H{"group_id": groupID},
+	})
+
+	party, err = h.getPartyWithMembers(ctx, party.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, viewFor(party, actorID))
+}
+
+// setGroup records accountID's sub-group. The capacity and lock checks and
+// the move share a transaction so concurrent moves can't overfill a
+// sub-group.
+func (h *Handler) setGroup(ctx context.Context, party *models.Party, accountID uuid.UUID, groupID *uuid.UUID) *opError {
+	partyID := party.ID
+	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if err := recheckLock(ctx, tx, party); err != nil {
+			return err
+		}
+		if groupID != nil {
+			spectator, err := tx.NewSelect().
+				Model((*models.PartyMember)(nil)).
+				Where("party_id = ? AND account_id = ? AND type = ?", partyID, accountID, models.MemberSpectator).
+				Exists(ctx)
+			if err != nil {
+				return err
+			}
+			if spectator {
+				return errIsSpectator
+			}
+
+			group := new(models.PartyGroup)
+			err = tx.NewSelect().Model(group).Where("id = ? AND party_id = ?", *groupID, partyID).Scan(ctx)
+			if errors.Is(err, sql.ErrNoRows) {
+				return errGroupNotFound
+			}
+			if err != nil {
+				return err
+			}
+			count, err := tx.NewSelect().
+				Model((*models.PartyMember)(nil)).
+				Where("party_id = ? AND group_id = ? AND account_id != ?", partyID, *groupID, accountID).
+				Count(ctx)
+			if err != nil {
+				return err
+			}
+			if count >= group.MaxSize {
+				return errGroupFull
+			}
+		}
+		res, err := tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("group_id = ?", groupID).
+			Where("party_id = ? AND account_id = ?", partyID, accountID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errNotInParty
+		}
+		return nil
+	})
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		return opErr
+	}
+	if err != nil {
+		return newOpError(http.StatusInternalServerError, "group_change_failed", "Failed to change sub-group")
+	}
+	return nil
+}
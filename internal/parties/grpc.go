@@ -0,0 +1,323 @@
+package parties
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	handv1 "github.com/bananalabs-oss/hand/proto/hand/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcService serves hand.v1.PartyService. It runs the same lookups and
+// lock operations as the internal HTTP routes; the tenants interceptors
+// authenticate each call.
+type grpcService struct {
+	handv1.UnimplementedPartyServiceServer
+	h *Handler
+}
+
+// RegisterGRPC registers the party service on srv. srv must be created with
+// tenants.Registry.GRPCServerOptions.
+func (h *Handler) RegisterGRPC(srv *grpc.Server) {
+	handv1.RegisterPartyServiceServer(srv, &grpcService{h: h})
+}
+
+func (s *grpcService) GetParty(ctx context.Context, req *handv1.GetPartyRequest) (*handv1.Party, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+	partyID, err := parseID(req.GetPartyId(), "party_id")
+	if err != nil {
+		return nil, err
+	}
+
+	party, opErr := s.h.lookupParty(ctx, caller.Tenant, partyID, req.GetGame())
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	return partyProto(party), nil
+}
+
+func (s *grpcService) GetPlayerParty(ctx context.Context, req *handv1.GetPlayerPartyRequest) (*handv1.Party, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := parseID(req.GetAccountId(), "account_id")
+	if err != nil {
+		return nil, err
+	}
+
+	party, opErr := s.h.lookupPlayerParty(ctx, scope{tenant: caller.Tenant, game: gameOrDefault(req.GetGame())}, accountID)
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	return partyProto(party), nil
+}
+
+func (s *grpcService) BatchGetParties(ctx context.Context, req *handv1.BatchGetPartiesRequest) (*handv1.BatchGetPartiesResponse, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+	partyIDs, err := parseIDs(req.GetPartyIds(), "party_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	parties, opErr := s.h.batchLookupParties(ctx, caller.Tenant, partyIDs, req.GetGame())
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	resp := &handv1.BatchGetPartiesResponse{Parties: make(map[string]*handv1.Party, len(parties))}
+	for id, party := range parties {
+		resp.Parties[id.String()] = partyProto(party)
+	}
+	return resp, nil
+}
+
+func (s *grpcService) BatchGetPlayerParties(ctx context.Context, req *handv1.BatchGetPlayerPartiesRequest) (*handv1.BatchGetPlayerPartiesResponse, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+	accountIDs, err := parseIDs(req.GetAccountIds(), "account_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	parties, opErr := s.h.batchLookupPlayerParties(ctx, scope{tenant: caller.Tenant, game: gameOrDefault(req.GetGame())}, accountIDs)
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	resp := &handv1.BatchGetPlayerPartiesResponse{Parties: make(map[string]*handv1.Party, len(parties))}
+	for id, party := range parties {
+		resp.Parties[id.String()] = partyProto(party)
+	}
+	return resp, nil
+}
+
+func (s *grpcService) LockParty(ctx context.Context, req *handv1.LockPartyRequest) (*handv1.Party, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeLock)
+	if err != nil {
+		return nil, err
+	}
+	partyID, err := parseID(req.GetPartyId(), "party_id")
+	if err != nil {
+		return nil, err
+	}
+
+	party, opErr := s.h.lockParty(ctx, caller, partyID, req.GetTtl().AsDuration())
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	return partyProto(party), nil
+}
+
+func (s *grpcService) UnlockParty(ctx context.Context, req *handv1.UnlockPartyRequest) (*handv1.Party, error) {
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeLock)
+	if err != nil {
+		return nil, err
+	}
+	partyID, err := parseID(req.GetPartyId(), "party_id")
+	if err != nil {
+		return nil, err
+	}
+
+	party, opErr := s.h.unlockParty(ctx, caller, partyID)
+	if opErr != nil {
+		return nil, grpcError(opErr)
+	}
+	return partyProto(party), nil
+}
+
+// WatchParty streams the party's events until it is disbanded, merged or split. Like a
+// WebSocket, a watcher that falls behind is dropped, with ResourceExhausted,
+// and should fetch the party again before watching anew.
+func (s *grpcService) WatchParty(req *handv1.WatchPartyRequest, stream grpc.ServerStreamingServer[handv1.PartyEvent]) error {
+	ctx := stream.Context()
+	caller, err := tenants.RequireScopeGRPC(ctx, tenants.ScopeRead)
+	if err != nil {
+		return err
+	}
+	partyID, err := parseID(req.GetPartyId(), "party_id")
+	if err != nil {
+		return err
+	}
+	if s.h.cfg.Events == nil {
+		return status.Error(codes.Unavailable, "Events are disabled")
+	}
+
+	// Subscribe before the lookup so no change slips in between.
+	sub := s.h.cfg.Events.Subscribe(events.PartyTopic(partyID))
+	defer sub.Close()
+	if _, opErr := s.h.lookupParty(ctx, caller.Tenant, partyID, ""); opErr != nil {
+		return grpcError(opErr)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Watcher fell behind")
+			}
+			msg, err := eventProto(ev)
+			if err != nil {
+				return status.Error(codes.Internal, "Failed to encode event")
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+			if ev.Type == events.PartyDisbanded || ev.Type == events.PartyMerged || ev.Type == events.PartySplit {
+				return nil
+			}
+		}
+	}
+}
+
+// grpcError maps an operation's HTTP status to the closest gRPC code.
+func grpcError(err *opError) error {
+	code := codes.Internal
+	switch err.status {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.resp.Error+": "+err.resp.Message)
+}
+
+func parseID(s, field string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid_id: "+field+" must be a UUID")
+	}
+	return id, nil
+}
+
+func parseIDs(ss []string, field string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(ss))
+	for _, s := range ss {
+		id, err := parseID(s, field)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func gameOrDefault(game string) string {
+	if game == "" {
+		return DefaultGame
+	}
+	return game
+}
+
+func partyProto(p *models.Party) *handv1.Party {
+	out := &handv1.Party{
+		Id:             p.ID.String(),
+		OwnerId:        p.OwnerID.String(),
+		Game:           p.Game,
+		MaxSize:        int32(p.MaxSize),
+		SpectatorSlots: int32(p.SpectatorSlots),
+		Name:           p.Name,
+		Description:    p.Description,
+		Emblem:         p.Emblem,
+		InviteCode:     p.InviteCode,
+		Metadata:       metadataProto(p.Metadata),
+		CreatedAt:      timestamppb.New(p.CreatedAt),
+		UpdatedAt:      timestamppb.New(p.UpdatedAt),
+	}
+	if p.ArchivedAt != nil {
+		out.ArchivedAt = timestamppb.New(*p.ArchivedAt)
+	}
+	if p.MergedInto != nil {
+		out.MergedInto = p.MergedInto.String()
+	}
+	if p.SplitFrom != nil {
+		out.SplitFrom = p.SplitFrom.String()
+	}
+	if isLocked(p, time.Now()) {
+		out.Lock = &handv1.Lock{LockedBy: p.LockedBy}
+		if p.LockedUntil != nil {
+			out.Lock.ExpiresAt = timestamppb.New(*p.LockedUntil)
+		}
+	}
+	for _, m := range p.Members {
+		member := &handv1.Member{
+			AccountId: m.AccountID.String(),
+			Role:      m.Role,
+			Type:      m.Type,
+			Ready:     m.Ready,
+			Presence:  m.Presence,
+			Metadata:  metadataProto(m.Metadata),
+			JoinedAt:  timestamppb.New(m.JoinedAt),
+		}
+		if !m.LastSeenAt.IsZero() {
+			member.LastSeenAt = timestamppb.New(m.LastSeenAt)
+		}
+		if m.GroupID != nil {
+			member.GroupId = m.GroupID.String()
+		}
+		out.Members = append(out.Members, member)
+	}
+	for _, g := range p.Groups {
+		out.Groups = append(out.Groups, &handv1.Group{
+			Id:        g.ID.String(),
+			Name:      g.Name,
+			MaxSize:   int32(g.MaxSize),
+			CreatedAt: timestamppb.New(g.CreatedAt),
+		})
+	}
+	return out
+}
+
+// metadataProto converts stored metadata, which always round-trips through
+// JSON, to a Struct.
+func metadataProto(m models.Metadata) *structpb.Struct {
+	out, err := structpb.NewStruct(m)
+	if err != nil {
+		return &structpb.Struct{}
+	}
+	return out
+}
+
+func eventProto(ev events.Event) (*handv1.PartyEvent, error) {
+	msg := &handv1.PartyEvent{
+		Type:      ev.Type,
+		PartyId:   ev.PartyID.String(),
+		AccountId: ev.AccountID.String(),
+		ActorId:   ev.ActorID.String(),
+		At:        timestamppb.New(ev.At),
+	}
+	if ev.Data != nil {
+		data, err := json.Marshal(ev.Data)
+		if err != nil {
+			return nil, err
+		}
+		msg.DataJson = string(data)
+	}
+	return msg, nil
+}
@@ -2,32 +2,81 @@ package parties
 
 import (
 	"context"
-	"crypto/rand"
 	"database/sql"
-	"encoding/hex"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/bananalabs-oss/hand/internal/blocks"
+	"github.com/bananalabs-oss/hand/internal/events"
 	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+	"github.com/bananalabs-oss/hand/internal/textfilter"
 	"github.com/bananalabs-oss/potassium/middleware"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
 
+// Reasons recorded on party.disbanded events.
+const (
+	DisbandByOwner   = "owner"
+	DisbandOwnerLeft = "owner_left"
+	DisbandAbandoned = "abandoned"
+	DisbandIdle      = "idle"
+)
+
+type Config struct {
+	// Limiter throttles join attempts with unknown invite codes, using the
+	// limit InvalidCodeLimit picks for the caller's tenant. Nil disables
+	// the check.
+	Limiter          *ratelimit.Limiter
+	InvalidCodeLimit func(*gin.Context) ratelimit.Limit
+
+	// InviteCodes is the format for new invite codes. The zero value uses
+	// DefaultInviteCodeFormat.
+	InviteCodes InviteCodeFormat
+
+	ChatRetention ChatRetention
+
+	// Events receives roster and chat changes. Nil disables publishing.
+	Events *events.Hub
+
+	Gateway GatewayConfig
+
+	Presence PresenceConfig
+	Expiry   ExpiryConfig
+
+	// Blocks is an external block graph checked alongside blocks stored by
+	// Hand. Nil uses stored blocks only.
+	Blocks blocks.Provider
+
+	// TextFilter screens party names and descriptions. Nil allows any text.
+	TextFilter textfilter.Filter
+
+	// Games holds per-namespace settings. When set, only these namespaces
+	// and DefaultGame are accepted.
+	Games map[string]GameConfig
+
+	// InviteTTL is how long a direct invite holds a player slot. Zero uses
+	// DefaultInviteTTL.
+	InviteTTL time.Duration
+}
+
 type Handler struct {
-	db *bun.DB
+	db      *bun.DB
+	cfg     Config
+	gateway *gateway
 }
 
-func NewHandler(db *bun.DB) *Handler {
-	return &Handler{db: db}
+func NewHandler(db *bun.DB, cfg Config) *Handler {
+	return &Handler{db: db, cfg: cfg, gateway: newGateway(cfg.Gateway)}
 }
 
-func generateInviteCode() string {
-	b := make([]byte, 4)
-	_, _ = rand.Read(b)
-	return hex.EncodeToString(b)
+func (h *Handler) generateInviteCode() string {
+	return h.cfg.InviteCodes.generate()
 }
 
 func getAccountID(c *gin.Context) (uuid.UUID, bool) {
@@ -42,11 +91,12 @@ func getAccountID(c *gin.Context) (uuid.UUID, bool) {
 	return parsed, true
 }
 
-func (h *Handler) findMembership(ctx context.Context, accountID uuid.UUID) (*models.PartyMember, error) {
+// findMembership returns the account's membership in the given scope.
+func (h *Handler) findMembership(ctx context.Context, sc scope, accountID uuid.UUID) (*models.PartyMember, error) {
 	member := new(models.PartyMember)
 	err := h.db.NewSelect().
 		Model(member).
-		Where("account_id = ?", accountID).
+		Where("tenant = ? AND game = ? AND account_id = ?", sc.tenant, sc.game, accountID).
 		Scan(ctx)
 	if err != nil {
 		return nil, err
@@ -54,16 +104,44 @@ func (h *Handler) findMembership(ctx context.Context, accountID uuid.UUID) (*mod
 	return member, nil
 }
 
+// findMember returns the account's membership in a specific party.
+func (h *Handler) findMember(ctx context.Context, partyID, accountID uuid.UUID) (*models.PartyMember, error) {
+	member := new(models.PartyMember)
+	err := h.db.NewSelect().
+		Model(member).
+		Where("party_id = ? AND account_id = ?", partyID, accountID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (h *Handler) getParty(ctx context.Context, partyID uuid.UUID) (*models.Party, error) {
+	party := new(models.Party)
+	err := h.db.NewSelect().
+		Model(party).
+		Where("p.id = ?", partyID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return party, nil
+}
+
 func (h *Handler) getPartyWithMembers(ctx context.Context, partyID uuid.UUID) (*models.Party, error) {
 	party := new(models.Party)
 	err := h.db.NewSelect().
 		Model(party).
 		Relation("Members").
+		Relation("Groups").
 		Where("p.id = ?", partyID).
 		Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
+	h.applyPresence(party)
+	hideExpiredLock(party)
 	return party, nil
 }
 
@@ -76,7 +154,8 @@ func (h *Handler) CreateParty(c *gin.Context) {
 		return
 	}
 
-	_, err := h.findMembership(ctx, accountID)
+	sc := getScope(c)
+	_, err := h.findMembership(ctx, sc, accountID)
 	if err == nil {
 		c.JSON(http.StatusConflict, middleware.ErrorResponse{
 			Error:   "already_in_party",
@@ -86,31 +165,46 @@ func (h *Handler) CreateParty(c *gin.Context) {
 	}
 
 	now := time.Now().UTC()
+	game := h.gameConfig(sc.game)
 	party := &models.Party{
-		ID:         uuid.New(),
-		OwnerID:    accountID,
-		InviteCode: generateInviteCode(),
-		MaxSize:    models.DefaultMaxSize,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:             uuid.New(),
+		OwnerID:        accountID,
+		Tenant:         sc.tenant,
+		Game:           sc.game,
+		MaxSize:        game.MaxSize,
+		SpectatorSlots: game.SpectatorSlots,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	member := &models.PartyMember{
-		PartyID:   party.ID,
-		AccountID: accountID,
-		Role:      models.RoleOwner,
-		JoinedAt:  now,
+		PartyID:    party.ID,
+		AccountID:  accountID,
+		Tenant:     sc.tenant,
+		Game:       sc.game,
+		Role:       models.RoleOwner,
+		Type:       models.MemberPlayer,
+		Presence:   models.PresenceOnline,
+		LastSeenAt: now,
+		JoinedAt:   now,
 	}
 
-	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		if _, err := tx.NewInsert().Model(party).Exec(ctx); err != nil {
-			return err
-		}
-		if _, err := tx.NewInsert().Model(member).Exec(ctx); err != nil {
-			return err
+	// Retry with a fresh code if the generated one is already taken
+	for attempt := 0; attempt < maxInviteCodeAttempts; attempt++ {
+		party.InviteCode = h.generateInviteCode()
+		err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+			if _, err := tx.NewInsert().Model(party).Exec(ctx); err != nil {
+				return err
+			}
+			if _, err := tx.NewInsert().Model(member).Exec(ctx); err != nil {
+				return err
+			}
+			return nil
+		})
+		if !isInviteCodeCollision(err) {
+			break
 		}
-		return nil
-	})
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
 			Error:   "create_failed",
@@ -120,6 +214,7 @@ func (h *Handler) CreateParty(c *gin.Context) {
 	}
 
 	party.Members = []models.PartyMember{*member}
+	h.publish(events.Event{Tenant: sc.tenant, Type: events.PartyCreated, PartyID: party.ID, AccountID: accountID, ActorID: accountID})
 	c.JSON(http.StatusCreated, party)
 }
 
@@ -130,7 +225,7 @@ func (h *Handler) GetMyParty(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
+	member, err := h.findMembership(ctx, getScope(c), accountID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "not_in_party",
@@ -148,7 +243,7 @@ func (h *Handler) GetMyParty(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, party)
+	c.JSON(http.StatusOK, viewFor(party, accountID))
 }
 
 func (h *Handler) JoinParty(c *gin.Context) {
@@ -160,6 +255,8 @@ func (h *Handler) JoinParty(c *gin.Context) {
 
 	var req struct {
 		InviteCode string `json:"invite_code" binding:"required"`
+		// Type is models.MemberSpectator to join as a spectator.
+		Type string `json:"type"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
@@ -168,8 +265,20 @@ func (h *Handler) JoinParty(c *gin.Context) {
 		})
 		return
 	}
+	if req.Type == "" {
+		req.Type = models.MemberPlayer
+	}
+	if req.Type != models.MemberPlayer && req.Type != models.MemberSpectator {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "type must be player or spectator",
+		})
+		return
+	}
+	req.InviteCode = strings.TrimSpace(req.InviteCode)
 
-	_, err := h.findMembership(ctx, accountID)
+	sc := getScope(c)
+	_, err := h.findMembership(ctx, sc, accountID)
 	if err == nil {
 		c.JSON(http.StatusConflict, middleware.ErrorResponse{
 			Error:   "already_in_party",
@@ -178,13 +287,23 @@ func (h *Handler) JoinParty(c *gin.Context) {
 		return
 	}
 
+	// Guessing codes is throttled much harder than joining: callers who
+	// have burned through their invalid-code budget are turned away before
+	// the lookup, so a valid guess can't be told apart from an invalid one.
+	if res := h.cfg.Limiter.Blocked(c, "join_invalid", h.invalidCodeLimit(c)); !res.Allowed {
+		ratelimit.Reject(c, res)
+		return
+	}
+
 	party := new(models.Party)
 	err = h.db.NewSelect().
 		Model(party).
 		Relation("Members").
-		Where("invite_code = ?", req.InviteCode).
+		Where("invite_code = ? COLLATE NOCASE AND tenant = ? AND game = ?", req.InviteCode, sc.tenant, sc.game).
+		Where("archived_at IS NULL").
 		Scan(ctx)
 	if err != nil {
+		h.cfg.Limiter.Allow(c, "join_invalid", h.invalidCodeLimit(c))
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "invalid_code",
 			Message: "Invalid invite code",
@@ -192,39 +311,82 @@ func (h *Handler) JoinParty(c *gin.Context) {
 		return
 	}
 
-	if len(party.Members) >= party.MaxSize {
-		c.JSON(http.StatusConflict, middleware.ErrorResponse{
-			Error:   "party_full",
-			Message: "Party is full",
-		})
+	h.joinParty(c, party, accountID, req.Type)
+}
+
+// joinParty adds accountID to party as memberType and responds with the
+// party. Player slots held by other players' invites count as taken, and
+// every invite the joiner held in the party's game is used up, freeing the
+// slots other parties were holding for them.
+func (h *Handler) joinParty(c *gin.Context, party *models.Party, accountID uuid.UUID, memberType string) {
+	ctx := c.Request.Context()
+	if isLocked(party, time.Now()) {
+		writeError(c, errPartyLocked)
+		return
+	}
+	if countType(party, memberType) >= capacity(party, memberType) {
+		writeError(c, errFull(memberType))
+		return
+	}
+
+	if err := h.checkBan(ctx, party.ID, accountID); err != nil {
+		writeError(c, err)
+		return
+	}
+	if err := h.checkBlocks(ctx, party.Tenant, accountID, memberIDs(party)); err != nil {
+		writeError(c, err)
 		return
 	}
 
+	now := time.Now().UTC()
 	member := &models.PartyMember{
-		PartyID:   party.ID,
-		AccountID: accountID,
-		Role:      models.RoleMember,
-		JoinedAt:  time.Now().UTC(),
+		PartyID:    party.ID,
+		AccountID:  accountID,
+		Tenant:     party.Tenant,
+		Game:       party.Game,
+		Role:       models.RoleMember,
+		Type:       memberType,
+		Presence:   models.PresenceOnline,
+		LastSeenAt: now,
+		JoinedAt:   now,
 	}
 
-	// Use a transaction with a re-check to prevent race condition on concurrent joins
-	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		count, err := tx.NewSelect().Model((*models.PartyMember)(nil)).Where("party_id = ?", party.ID).Count(ctx)
+	// Use a transaction with a re-check to prevent race condition on concurrent joins.
+	// Players and spectators are checked against their own capacity.
+	var dropped []models.PartyInvite
+	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if err := recheckLock(ctx, tx, party); err != nil {
+			return err
+		}
+		count, err := countTaken(ctx, tx, party.ID, memberType, accountID)
 		if err != nil {
 			return err
 		}
-		if count >= party.MaxSize {
-			return fmt.Errorf("party_full")
+		if count >= capacity(party, memberType) {
+			return errFull(memberType)
 		}
-		_, err = tx.NewInsert().Model(member).Exec(ctx)
+		if _, err := tx.NewInsert().Model(member).Exec(ctx); err != nil {
+			return err
+		}
+
+		err = tx.NewSelect().
+			Model(&dropped).
+			Where("tenant = ? AND game = ? AND account_id = ?", party.Tenant, party.Game, accountID).
+			Where("party_id != ? AND expires_at > ?", party.ID, now).
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewDelete().
+			Model((*models.PartyInvite)(nil)).
+			Where("tenant = ? AND game = ? AND account_id = ?", party.Tenant, party.Game, accountID).
+			Exec(ctx)
 		return err
 	})
 	if err != nil {
-		if err.Error() == "party_full" {
-			c.JSON(http.StatusConflict, middleware.ErrorResponse{
-				Error:   "party_full",
-				Message: "Party is full",
-			})
+		var opErr *opError
+		if errors.As(err, &opErr) {
+			writeError(c, opErr)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
@@ -234,6 +396,11 @@ func (h *Handler) JoinParty(c *gin.Context) {
 		return
 	}
 
+	h.publish(events.Event{Tenant: party.Tenant, Type: events.MemberJoined, PartyID: party.ID, AccountID: accountID, ActorID: accountID})
+	for i := range dropped {
+		h.publishInviteCancelled(&dropped[i], accountID, "joined_elsewhere")
+	}
+
 	party, err = h.getPartyWithMembers(ctx, party.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
@@ -242,7 +409,7 @@ func (h *Handler) JoinParty(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, party)
+	c.JSON(http.StatusOK, viewFor(party, accountID))
 }
 
 func (h *Handler) LeaveParty(c *gin.Context) {
@@ -252,7 +419,7 @@ func (h *Handler) LeaveParty(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
+	member, err := h.findMembership(ctx, getScope(c), accountID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "not_in_party",
@@ -262,7 +429,7 @@ func (h *Handler) LeaveParty(c *gin.Context) {
 	}
 
 	if member.Role == models.RoleOwner {
-		h.disbandParty(c, ctx, member.PartyID)
+		h.disbandParty(c, ctx, member.Tenant, member.PartyID, accountID, DisbandOwnerLeft)
 		return
 	}
 
@@ -278,9 +445,18 @@ func (h *Handler) LeaveParty(c *gin.Context) {
 		return
 	}
 
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.MemberLeft, PartyID: member.PartyID, AccountID: accountID, ActorID: accountID})
 	c.JSON(http.StatusOK, gin.H{"message": "Left party"})
 }
 
+// kickRequest is the body of POST /parties/kick and the kick socket
+// command. Ban keeps the player out of the party, for BanDuration if set.
+type kickRequest struct {
+	AccountID   uuid.UUID `json:"account_id" binding:"required"`
+	Ban         bool      `json:"ban"`
+	BanDuration string    `json:"ban_duration"`
+}
+
 func (h *Handler) KickMember(c *gin.Context) {
 	ctx := c.Request.Context()
 	accountID, ok := getAccountID(c)
@@ -288,9 +464,7 @@ func (h *Handler) KickMember(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		AccountID uuid.UUID `json:"account_id" binding:"required"`
-	}
+	var req kickRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
 			Error:   "invalid_request",
@@ -299,45 +473,142 @@ func (h *Handler) KickMember(c *gin.Context) {
 		return
 	}
 
-	if req.AccountID == accountID {
+	if err := h.kickMember(ctx, getScope(c), accountID, req); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if req.Ban {
+		c.JSON(http.StatusOK, gin.H{"message": "Member kicked and banned"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member kicked"})
+}
+
+func (h *Handler) kickMember(ctx context.Context, sc scope, accountID uuid.UUID, req kickRequest) *opError {
+	targetID := req.AccountID
+	if targetID == accountID {
+		return newOpError(http.StatusBadRequest, "invalid_request", "Cannot kick yourself. Use leave.")
+	}
+
+	var ban *models.PartyBan
+	if req.Ban {
+		ban = &models.PartyBan{AccountID: targetID, BannedBy: accountID, CreatedAt: time.Now().UTC()}
+		if req.BanDuration != "" {
+			d, err := time.ParseDuration(req.BanDuration)
+			if err != nil || d <= 0 {
+				return newOpError(http.StatusBadRequest, "invalid_request", "ban_duration must be a positive duration such as 24h")
+			}
+			expires := ban.CreatedAt.Add(d)
+			ban.ExpiresAt = &expires
+		}
+	}
+
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		return errNotInParty
+	}
+	party, opErr := h.authorizeMember(ctx, member, PermKick)
+	if opErr != nil {
+		return opErr
+	}
+	if ban != nil {
+		if err := authorize(party, member, PermBan); err != nil {
+			return err
+		}
+		ban.PartyID = party.ID
+	}
+
+	target, err := h.findMember(ctx, member.PartyID, targetID)
+	if err != nil {
+		return newOpError(http.StatusNotFound, "not_in_party", "That player is not in your party")
+	}
+	if err := authorizeOver(party, member, target, PermKick); err != nil {
+		return err
+	}
+	if isLocked(party, time.Now()) {
+		return errPartyLocked
+	}
+
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if err := recheckLock(ctx, tx, party); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().
+			Model((*models.PartyMember)(nil)).
+			Where("party_id = ? AND account_id = ?", member.PartyID, targetID).
+			Exec(ctx)
+		if err != nil || ban == nil {
+			return err
+		}
+		return upsertBan(ctx, tx, ban)
+	})
+	if errors.As(err, &opErr) {
+		return opErr
+	}
+	if err != nil {
+		return newOpError(http.StatusInternalServerError, "kick_failed", "Failed to kick member")
+	}
+
+	ev := events.Event{Tenant: member.Tenant, Type: events.MemberKicked, PartyID: member.PartyID, AccountID: targetID, ActorID: accountID}
+	if ban != nil {
+		ev.Data = gin.H{"banned": true, "expires_at": ban.ExpiresAt}
+	}
+	h.publish(ev)
+	return nil
+}
+
+// SetReady marks the caller as ready (or not) for the next match.
+func (h *Handler) SetReady(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Ready *bool `json:"ready" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
 			Error:   "invalid_request",
-			Message: "Cannot kick yourself. Use leave.",
+			Message: "ready is required",
 		})
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
-	if err != nil || member.Role != models.RoleOwner {
-		c.JSON(http.StatusForbidden, middleware.ErrorResponse{
-			Error:   "not_owner",
-			Message: "Only the party owner can kick members",
-		})
+	if err := h.setReady(ctx, getScope(c), accountID, *req.Ready); err != nil {
+		writeError(c, err)
 		return
 	}
 
-	target, err := h.findMembership(ctx, req.AccountID)
-	if err != nil || target.PartyID != member.PartyID {
-		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
-			Error:   "not_in_party",
-			Message: "That player is not in your party",
-		})
-		return
+	c.JSON(http.StatusOK, gin.H{"ready": *req.Ready})
+}
+
+func (h *Handler) setReady(ctx context.Context, sc scope, accountID uuid.UUID, ready bool) *opError {
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		return errNotInParty
 	}
 
-	_, err = h.db.NewDelete().
+	_, err = h.db.NewUpdate().
 		Model((*models.PartyMember)(nil)).
-		Where("party_id = ? AND account_id = ?", member.PartyID, req.AccountID).
+		Set("ready = ?", ready).
+		Where("party_id = ? AND account_id = ?", member.PartyID, accountID).
 		Exec(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
-			Error:   "kick_failed",
-			Message: "Failed to kick member",
-		})
-		return
+		return newOpError(http.StatusInternalServerError, "ready_failed", "Failed to update ready state")
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Member kicked"})
+	h.publish(events.Event{
+		Tenant:    member.Tenant,
+		Type:      events.MemberReady,
+		PartyID:   member.PartyID,
+		AccountID: accountID,
+		ActorID:   accountID,
+		Data:      gin.H{"ready": ready},
+	})
+	return nil
 }
 
 func (h *Handler) TransferOwnership(c *gin.Context) {
@@ -366,17 +637,21 @@ func (h *Handler) TransferOwnership(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
-	if err != nil || member.Role != models.RoleOwner {
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
 		c.JSON(http.StatusForbidden, middleware.ErrorResponse{
 			Error:   "not_owner",
 			Message: "Only the party owner can transfer ownership",
 		})
 		return
 	}
+	if _, err := h.authorizeMember(ctx, member, PermTransfer); err != nil {
+		writeError(c, err)
+		return
+	}
 
-	target, err := h.findMembership(ctx, req.AccountID)
-	if err != nil || target.PartyID != member.PartyID {
+	_, err = h.findMember(ctx, member.PartyID, req.AccountID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "not_in_party",
 			Message: "That player is not in your party",
@@ -419,12 +694,14 @@ func (h *Handler) TransferOwnership(c *gin.Context) {
 		return
 	}
 
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.PartyOwnerChanged, PartyID: member.PartyID, AccountID: req.AccountID, ActorID: accountID})
+
 	party, err := h.getPartyWithMembers(ctx, member.PartyID)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{"status": "transferred"})
 		return
 	}
-	c.JSON(http.StatusOK, party)
+	c.JSON(http.StatusOK, viewFor(party, accountID))
 }
 
 func (h *Handler) DisbandParty(c *gin.Context) {
@@ -434,7 +711,7 @@ func (h *Handler) DisbandParty(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
+	member, err := h.findMembership(ctx, getScope(c), accountID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "not_in_party",
@@ -443,15 +720,12 @@ func (h *Handler) DisbandParty(c *gin.Context) {
 		return
 	}
 
-	if member.Role != models.RoleOwner {
-		c.JSON(http.StatusForbidden, middleware.ErrorResponse{
-			Error:   "not_owner",
-			Message: "Only the party owner can disband",
-		})
+	if _, err := h.authorizeMember(ctx, member, PermDisband); err != nil {
+		writeError(c, err)
 		return
 	}
 
-	h.disbandParty(c, ctx, member.PartyID)
+	h.disbandParty(c, ctx, member.Tenant, member.PartyID, accountID, DisbandByOwner)
 }
 
 func (h *Handler) RegenerateInvite(c *gin.Context) {
@@ -461,7 +735,7 @@ func (h *Handler) RegenerateInvite(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, accountID)
+	member, err := h.findMembership(ctx, getScope(c), accountID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
 			Error:   "not_in_party",
@@ -470,21 +744,24 @@ func (h *Handler) RegenerateInvite(c *gin.Context) {
 		return
 	}
 
-	if member.Role != models.RoleOwner {
-		c.JSON(http.StatusForbidden, middleware.ErrorResponse{
-			Error:   "not_owner",
-			Message: "Only the party owner can regenerate invites",
-		})
+	if _, err := h.authorizeMember(ctx, member, PermRegenerateInvite); err != nil {
+		writeError(c, err)
 		return
 	}
 
-	newCode := generateInviteCode()
-	_, err = h.db.NewUpdate().
-		Model((*models.Party)(nil)).
-		Set("invite_code = ?", newCode).
-		Set("updated_at = ?", time.Now().UTC()).
-		Where("id = ?", member.PartyID).
-		Exec(ctx)
+	var newCode string
+	for attempt := 0; attempt < maxInviteCodeAttempts; attempt++ {
+		newCode = h.generateInviteCode()
+		_, err = h.db.NewUpdate().
+			Model((*models.Party)(nil)).
+			Set("invite_code = ?", newCode).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("id = ?", member.PartyID).
+			Exec(ctx)
+		if !isInviteCodeCollision(err) {
+			break
+		}
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
 			Error:   "regenerate_failed",
@@ -493,6 +770,9 @@ func (h *Handler) RegenerateInvite(c *gin.Context) {
 		return
 	}
 
+	// The new code isn't broadcast: members the party's policy lets share
+	// it fetch it from /parties/mine.
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.PartyInviteChanged, PartyID: member.PartyID, AccountID: accountID, ActorID: accountID})
 	c.JSON(http.StatusOK, gin.H{"invite_code": newCode})
 }
 
@@ -509,12 +789,9 @@ func (h *Handler) GetPartyByID(c *gin.Context) {
 		return
 	}
 
-	party, err := h.getPartyWithMembers(ctx, partyID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
-			Error:   "not_found",
-			Message: "Party not found",
-		})
+	party, opErr := h.lookupParty(ctx, getTenant(c), partyID, c.Query("game"))
+	if opErr != nil {
+		writeError(c, opErr)
 		return
 	}
 
@@ -532,46 +809,186 @@ func (h *Handler) GetPlayerParty(c *gin.Context) {
 		return
 	}
 
-	member, err := h.findMembership(ctx, userID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
-			Error:   "not_in_party",
-			Message: "Player is not in a party",
+	sc := scope{tenant: getTenant(c), game: c.DefaultQuery("game", DefaultGame)}
+	party, opErr := h.lookupPlayerParty(ctx, sc, userID)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, party)
+}
+
+// batchRequest is the body of the internal batch lookups. Game filters
+// party lookups and picks the namespace for player lookups.
+type batchRequest struct {
+	PartyIDs   []uuid.UUID `json:"party_ids"`
+	AccountIDs []uuid.UUID `json:"account_ids"`
+	Game       string      `json:"game"`
+}
+
+// BatchGetParties returns the requested parties keyed by ID, leaving out
+// any that don't exist.
+func (h *Handler) BatchGetParties(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "party_ids must be a list of party IDs",
 		})
 		return
 	}
 
-	party, err := h.getPartyWithMembers(ctx, member.PartyID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
-			Error:   "fetch_failed",
-			Message: "Failed to fetch party",
+	parties, opErr := h.batchLookupParties(c.Request.Context(), getTenant(c), req.PartyIDs, req.Game)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"parties": parties})
+}
+
+// BatchGetPlayerParties returns the parties of the requested players keyed
+// by account ID, leaving out players who aren't in one.
+func (h *Handler) BatchGetPlayerParties(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "account_ids must be a list of account IDs",
 		})
 		return
 	}
+	if req.Game == "" {
+		req.Game = DefaultGame
+	}
 
-	c.JSON(http.StatusOK, party)
+	parties, opErr := h.batchLookupPlayerParties(c.Request.Context(), scope{tenant: getTenant(c), game: req.Game}, req.AccountIDs)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"parties": parties})
 }
 
-// --- Helpers ---
+// The lookups below back both the internal HTTP routes and the gRPC
+// service.
 
-func (h *Handler) disbandParty(c *gin.Context, ctx context.Context, partyID uuid.UUID) {
-	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		_, err := tx.NewDelete().
-			Model((*models.PartyMember)(nil)).
-			Where("party_id = ?", partyID).
-			Exec(ctx)
-		if err != nil {
-			return err
-		}
+// maxBatchSize bounds the IDs accepted by one batch lookup.
+const maxBatchSize = 100
 
-		_, err = tx.NewDelete().
-			Model((*models.Party)(nil)).
-			Where("id = ?", partyID).
-			Exec(ctx)
-		return err
-	})
+var (
+	errPartyNotFound = newOpError(http.StatusNotFound, "not_found", "Party not found")
+	errBatchSize     = newOpError(http.StatusBadRequest, "invalid_request", "Batch lookups take between 1 and "+strconv.Itoa(maxBatchSize)+" IDs")
+)
+
+// lookupParty returns a tenant's party with its members. A non-empty game
+// must match the party's.
+func (h *Handler) lookupParty(ctx context.Context, tenant string, partyID uuid.UUID, game string) (*models.Party, *opError) {
+	party := new(models.Party)
+	err := h.db.NewSelect().
+		Model(party).
+		Relation("Members").
+		Relation("Groups").
+		Where("p.id = ? AND p.tenant = ?", partyID, tenant).
+		Scan(ctx)
+	if err != nil || (game != "" && game != party.Game) {
+		return nil, errPartyNotFound
+	}
+	h.applyPresence(party)
+	hideExpiredLock(party)
+	return party, nil
+}
+
+func (h *Handler) lookupPlayerParty(ctx context.Context, sc scope, accountID uuid.UUID) (*models.Party, *opError) {
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		return nil, newOpError(http.StatusNotFound, "not_in_party", "Player is not in a party")
+	}
+
+	party, err := h.getPartyWithMembers(ctx, member.PartyID)
 	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch party")
+	}
+	return party, nil
+}
+
+func (h *Handler) batchLookupParties(ctx context.Context, tenant string, partyIDs []uuid.UUID, game string) (map[uuid.UUID]*models.Party, *opError) {
+	if len(partyIDs) == 0 || len(partyIDs) > maxBatchSize {
+		return nil, errBatchSize
+	}
+
+	var parties []*models.Party
+	q := h.db.NewSelect().
+		Model(&parties).
+		Relation("Members").
+		Relation("Groups").
+		Where("p.id IN (?) AND p.tenant = ?", bun.In(partyIDs), tenant)
+	if game != "" {
+		q = q.Where("p.game = ?", game)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch parties")
+	}
+
+	out := make(map[uuid.UUID]*models.Party, len(parties))
+	for _, party := range parties {
+		h.applyPresence(party)
+		hideExpiredLock(party)
+		out[party.ID] = party
+	}
+	return out, nil
+}
+
+func (h *Handler) batchLookupPlayerParties(ctx context.Context, sc scope, accountIDs []uuid.UUID) (map[uuid.UUID]*models.Party, *opError) {
+	if len(accountIDs) == 0 || len(accountIDs) > maxBatchSize {
+		return nil, errBatchSize
+	}
+
+	var members []models.PartyMember
+	err := h.db.NewSelect().
+		Model(&members).
+		Where("tenant = ? AND game = ? AND account_id IN (?)", sc.tenant, sc.game, bun.In(accountIDs)).
+		Scan(ctx)
+	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch parties")
+	}
+	if len(members) == 0 {
+		return map[uuid.UUID]*models.Party{}, nil
+	}
+
+	partyIDs := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		partyIDs = append(partyIDs, m.PartyID)
+	}
+	parties, opErr := h.batchLookupParties(ctx, sc.tenant, partyIDs, "")
+	if opErr != nil {
+		return nil, opErr
+	}
+
+	out := make(map[uuid.UUID]*models.Party, len(members))
+	for _, m := range members {
+		if party, ok := parties[m.PartyID]; ok {
+			out[m.AccountID] = party
+		}
+	}
+	return out, nil
+}
+
+// --- Helpers ---
+
+func (h *Handler) invalidCodeLimit(c *gin.Context) ratelimit.Limit {
+	if h.cfg.InvalidCodeLimit == nil {
+		return ratelimit.Limit{}
+	}
+	return h.cfg.InvalidCodeLimit(c)
+}
+
+func (h *Handler) publish(ev events.Event) {
+	h.cfg.Events.Publish(ev)
+}
+
+func (h *Handler) disbandParty(c *gin.Context, ctx context.Context, tenant string, partyID, actorID uuid.UUID, reason string) {
+	if err := h.disband(ctx, tenant, partyID, actorID, reason); err != nil {
 		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
 			Error:   "disband_failed",
 			Message: "Failed to disband party",
@@ -581,3 +998,84 @@ func (h *Handler) disbandParty(c *gin.Context, ctx context.Context, partyID uuid
 
 	c.JSON(http.StatusOK, gin.H{"message": "Party disbanded"})
 }
+
+// disband deletes a party with its members and chat history, then notifies
+// the members. reason is included in the party.disbanded event.
+func (h *Handler) disband(ctx context.Context, tenant string, partyID, actorID uuid.UUID, reason string) error {
+	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		return deleteParty(ctx, tx, partyID)
+	})
+	if err != nil {
+		return err
+	}
+	h.publishDisbanded(tenant, partyID, actorID, reason)
+	return nil
+}
+
+// deleteParty removes a party and everything that belongs to it.
+func deleteParty(ctx context.Context, tx bun.Tx, partyID uuid.UUID) error {
+	_, err := tx.NewDelete().
+		Model((*models.PartyMessage)(nil)).
+		Where("party_id = ?", partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.PartyBan)(nil)).
+		Where("party_id = ?", partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.PartyMember)(nil)).
+		Where("party_id = ?", partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.PartyGroup)(nil)).
+		Where("party_id = ?", partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.PartyMerge)(nil)).
+		Where("source_id = ? OR target_id = ?", partyID, partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.PartyInvite)(nil)).
+		Where("party_id = ?", partyID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewDelete().
+		Model((*models.Party)(nil)).
+		Where("id = ?", partyID).
+		Exec(ctx)
+	return err
+}
+
+func (h *Handler) publishDisbanded(tenant string, partyID, actorID uuid.UUID, reason string) {
+	h.publish(events.Event{
+		Tenant:    tenant,
+		Type:      events.PartyDisbanded,
+		PartyID:   partyID,
+		AccountID: actorID,
+		ActorID:   actorID,
+		Data:      gin.H{"reason": reason},
+	})
+}
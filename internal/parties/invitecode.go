@@ -0,0 +1,138 @@
+package parties
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	InviteCodeChars = "chars"
+	InviteCodeWords = "words"
+
+	// inviteAlphabet leaves out 0/O and 1/I so codes survive being read
+	// aloud or copied off a screen.
+	inviteAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// maxInviteCodeAttempts bounds retries when a generated code collides
+	// with an existing party's code.
+	maxInviteCodeAttempts = 5
+
+	// minInviteWordBits is the least entropy a words-style code may have,
+	// so it is no easier to guess than a short character code.
+	minInviteWordBits = 45
+	// defaultInviteWords yields codes like
+	// "maple-rocket-otter-cedar-violin-heron" (~47 bits).
+	defaultInviteWords = 6
+)
+
+// InviteCodeFormat controls how invite codes are generated. Length counts
+// characters for InviteCodeChars and words for InviteCodeWords.
+type InviteCodeFormat struct {
+	Style  string
+	Length int
+}
+
+// DefaultInviteCodeFormat yields codes like "K7QX3MZ9PA" (~50 bits).
+var DefaultInviteCodeFormat = InviteCodeFormat{Style: InviteCodeChars, Length: 10}
+
+// NewInviteCodeFormat validates a style and length. A zero length picks the
+// default for the style.
+func NewInviteCodeFormat(style string, length int) (InviteCodeFormat, error) {
+	switch style {
+	case "", InviteCodeChars:
+		if length == 0 {
+			length = DefaultInviteCodeFormat.Length
+		}
+		if length < 6 || length > 32 {
+			return InviteCodeFormat{}, fmt.Errorf("invite code length must be between 6 and 32 characters")
+		}
+		return InviteCodeFormat{Style: InviteCodeChars, Length: length}, nil
+	case InviteCodeWords:
+		if length == 0 {
+			length = defaultInviteWords
+		}
+		if minWords := minInviteWords(); length < minWords || length > 8 {
+			return InviteCodeFormat{}, fmt.Errorf("invite code length must be between %d and 8 words", minWords)
+		}
+		return InviteCodeFormat{Style: InviteCodeWords, Length: length}, nil
+	default:
+		return InviteCodeFormat{}, fmt.Errorf("unknown invite code style %q", style)
+	}
+}
+
+func (f InviteCodeFormat) generate() string {
+	if f.Style == InviteCodeWords {
+		words := make([]string, f.Length)
+		for i := range words {
+			words[i] = inviteWords[randomIndex(len(inviteWords))]
+		}
+		return strings.Join(words, "-")
+	}
+
+	length := f.Length
+	if length == 0 {
+		length = DefaultInviteCodeFormat.Length
+	}
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = inviteAlphabet[randomIndex(len(inviteAlphabet))]
+	}
+	return string(b)
+}
+
+// minInviteWords is the fewest words that give a code minInviteWordBits
+// of entropy.
+func minInviteWords() int {
+	return int(math.Ceil(minInviteWordBits / math.Log2(float64(len(inviteWords)))))
+}
+
+func randomIndex(n int) int {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return int(i.Int64())
+}
+
+// isInviteCodeCollision reports whether err is the unique constraint on
+// parties.invite_code rejecting a freshly generated code.
+func isInviteCodeCollision(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed: parties.invite_code")
+}
+
+// inviteWords are short, distinct words that are easy to say and spell.
+var inviteWords = []string{
+	"acorn", "agent", "alarm", "album", "amber", "anchor", "angle", "apple",
+	"apron", "arrow", "atlas", "attic", "autumn", "award", "bacon", "badge",
+	"bagel", "ballad", "bamboo", "banjo", "barrel", "basil", "beacon", "beetle",
+	"bench", "berry", "bison", "blade", "blanket", "blossom", "bonfire", "border",
+	"bottle", "branch", "bread", "brick", "bridge", "bronze", "bubble", "bucket",
+	"buffalo", "bugle", "butter", "cabin", "cactus", "camel", "camera", "candle",
+	"canyon", "captain", "carpet", "carrot", "castle", "cedar", "cello", "chalk",
+	"cherry", "chess", "circus", "clover", "cobalt", "cocoa", "comet", "compass",
+	"copper", "coral", "cotton", "cougar", "cradle", "crane", "crater", "crayon",
+	"cricket", "crystal", "cypress", "daisy", "dancer", "delta", "desert", "diamond",
+	"dolphin", "donkey", "dragon", "drum", "eagle", "echo", "elbow", "ember",
+	"engine", "falcon", "feather", "fiddle", "finch", "flame", "flute", "forest",
+	"fossil", "fountain", "fox", "galaxy", "garden", "garlic", "geyser", "ginger",
+	"glacier", "goblin", "gopher", "granite", "grape", "gravel", "guitar", "hammer",
+	"harbor", "harp", "hazel", "helmet", "heron", "hippo", "honey", "horizon",
+	"husky", "igloo", "island", "ivory", "jacket", "jaguar", "jasmine", "jelly",
+	"jigsaw", "jungle", "kayak", "kettle", "kiwi", "koala", "ladder", "lagoon",
+	"lantern", "lemon", "lilac", "lizard", "llama", "lobster", "locket", "lotus",
+	"magnet", "mango", "maple", "marble", "meadow", "melon", "meteor", "mirror",
+	"mitten", "monkey", "moose", "mosaic", "muffin", "nectar", "needle", "nickel",
+	"noodle", "nutmeg", "oasis", "ocean", "olive", "onion", "orbit", "orchid",
+	"otter", "oyster", "paddle", "panda", "parrot", "peach", "pebble", "pepper",
+	"piano", "pickle", "pilot", "pirate", "planet", "plum", "pocket", "polar",
+	"pony", "poppy", "potato", "prism", "pumpkin", "puzzle", "quartz", "quill",
+	"rabbit", "radar", "raven", "reef", "ribbon", "river", "robin", "rocket",
+	"saddle", "salmon", "satin", "scarf", "shadow", "shell", "silver", "sketch",
+	"sparrow", "spider", "spruce", "squid", "stone", "summit", "sunset", "swan",
+	"tango", "temple", "thunder", "tiger", "timber", "toast", "tomato", "topaz",
+	"tractor", "tulip", "tundra", "turtle", "umbrella", "valley", "velvet", "violin",
+	"volcano", "waffle", "walnut", "walrus", "willow", "window", "wizard", "yogurt",
+}
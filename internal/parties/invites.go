@@ -0,0 +1,397 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// DefaultInviteTTL is how long a direct invite holds its slot when
+// Config.InviteTTL is zero.
+const DefaultInviteTTL = 5 * time.Minute
+
+var (
+	errNoInvite       = newOpError(http.StatusNotFound, "no_invite", "No pending invite from that party")
+	errInviteeBanned  = newOpError(http.StatusForbidden, "banned", "That player is banned from your party")
+	errInviteeBlocked = newOpError(http.StatusForbidden, "blocked", "A block between that player and a member of your party prevents this")
+)
+
+// partyInvites is the body of GET /parties/invites.
+type partyInvites struct {
+	Incoming []models.PartyInvite `json:"incoming"`
+	Outgoing []models.PartyInvite `json:"outgoing"`
+}
+
+// countReserved counts the unexpired invites holding player slots in
+// partyID, leaving out any held for except.
+func countReserved(ctx context.Context, db bun.IDB, partyID, except uuid.UUID) (int, error) {
+	return db.NewSelect().
+		Model((*models.PartyInvite)(nil)).
+		Where("party_id = ? AND account_id != ? AND expires_at > ?", partyID, except, time.Now().UTC()).
+		Count(ctx)
+}
+
+// countTaken counts the party's slots of memberType that are filled or,
+// for players, reserved by an invite for someone other than accountID.
+func countTaken(ctx context.Context, db bun.IDB, partyID uuid.UUID, memberType string, accountID uuid.UUID) (int, error) {
+	count, err := countMembers(ctx, db, memberType, partyID)
+	if err != nil || memberType != models.MemberPlayer {
+		return count, err
+	}
+	reserved, err := countReserved(ctx, db, partyID, accountID)
+	return count + reserved, err
+}
+
+func (h *Handler) inviteTTL() time.Duration {
+	if h.cfg.InviteTTL <= 0 {
+		return DefaultInviteTTL
+	}
+	return h.cfg.InviteTTL
+}
+
+// SendInvite invites a player into the caller's party and reserves a
+// player slot for them until the invite expires. Inviting the same player
+// again renews the invite.
+func (h *Handler) SendInvite(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AccountID uuid.UUID `json:"account_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "account_id is required",
+		})
+		return
+	}
+	if req.AccountID == accountID {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Cannot invite yourself",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermInvite); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	party, err := h.getPartyWithMembers(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	if isLocked(party, time.Now()) {
+		writeError(c, errPartyLocked)
+		return
+	}
+	for _, m := range party.Members {
+		if m.AccountID == req.AccountID {
+			c.JSON(http.StatusConflict, middleware.ErrorResponse{
+				Error:   "already_in_party",
+				Message: "That player is already in your party",
+			})
+			return
+		}
+	}
+
+	if opErr := h.checkBan(ctx, party.ID, req.AccountID); opErr != nil {
+		if opErr == errBanned {
+			opErr = errInviteeBanned
+		}
+		writeError(c, opErr)
+		return
+	}
+	if opErr := h.checkBlocks(ctx, party.Tenant, req.AccountID, memberIDs(party)); opErr != nil {
+		if opErr == errBlocked {
+			opErr = errInviteeBlocked
+		}
+		writeError(c, opErr)
+		return
+	}
+
+	now := time.Now().UTC()
+	invite := &models.PartyInvite{
+		PartyID:   party.ID,
+		AccountID: req.AccountID,
+		Tenant:    party.Tenant,
+		Game:      party.Game,
+		InvitedBy: accountID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(h.inviteTTL()),
+	}
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.PartyInvite)(nil)).
+			Where("party_id = ? AND expires_at <= ?", party.ID, now).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := recheckLock(ctx, tx, party); err != nil {
+			return err
+		}
+		count, err := countTaken(ctx, tx, party.ID, models.MemberPlayer, req.AccountID)
+		if err != nil {
+			return err
+		}
+		if count >= party.MaxSize {
+			return errPartyFull
+		}
+		_, err = tx.NewInsert().
+			Model(invite).
+			On("CONFLICT (party_id, account_id) DO UPDATE").
+			Set("invited_by = EXCLUDED.invited_by").
+			Set("created_at = EXCLUDED.created_at").
+			Set("expires_at = EXCLUDED.expires_at").
+			Exec(ctx)
+		return err
+	})
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		writeError(c, opErr)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "invite_failed",
+			Message: "Failed to send invite",
+		})
+		return
+	}
+
+	h.publish(events.Event{
+		Tenant:    party.Tenant,
+		Type:      events.MemberInvited,
+		PartyID:   party.ID,
+		AccountID: req.AccountID,
+		ActorID:   accountID,
+		Data:      gin.H{"expires_at": invite.ExpiresAt},
+	})
+	c.JSON(http.StatusCreated, invite)
+}
+
+// GetInvites lists the caller's pending invites and those sent by their
+// party.
+func (h *Handler) GetInvites(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	sc := getScope(c)
+	now := time.Now().UTC()
+	out := partyInvites{Incoming: []models.PartyInvite{}, Outgoing: []models.PartyInvite{}}
+	q := h.db.NewSelect().
+		Model(&out.Incoming).
+		Where("tenant = ? AND game = ? AND account_id = ?", sc.tenant, sc.game, accountID).
+		Where("expires_at > ?", now).
+		OrderExpr("created_at ASC")
+	if err := q.Scan(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch invites",
+		})
+		return
+	}
+
+	if member, err := h.findMembership(ctx, sc, accountID); err == nil {
+		q := h.db.NewSelect().
+			Model(&out.Outgoing).
+			Where("party_id = ? AND expires_at > ?", member.PartyID, now).
+			OrderExpr("created_at ASC")
+		if err := q.Scan(ctx); err != nil {
+			c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+				Error:   "fetch_failed",
+				Message: "Failed to fetch invites",
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RevokeInvite withdraws the party's invite to the player in the path and
+// frees the slot it held.
+func (h *Handler) RevokeInvite(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	inviteeID, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid account ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermInvite); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	invite := new(models.PartyInvite)
+	err = h.db.NewDelete().
+		Model(invite).
+		Where("party_id = ? AND account_id = ? AND expires_at > ?", member.PartyID, inviteeID, time.Now().UTC()).
+		Returning("*").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "no_invite",
+			Message: "Your party has no pending invite for that player",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "invite_failed",
+			Message: "Failed to revoke invite",
+		})
+		return
+	}
+
+	h.publishInviteCancelled(invite, accountID, "revoked")
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked"})
+}
+
+// DeclineInvite turns down the invite from the party in the path and
+// frees the slot it held.
+func (h *Handler) DeclineInvite(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	partyID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	sc := getScope(c)
+	invite := new(models.PartyInvite)
+	err = h.db.NewDelete().
+		Model(invite).
+		Where("party_id = ? AND account_id = ? AND tenant = ? AND game = ?", partyID, accountID, sc.tenant, sc.game).
+		Where("expires_at > ?", time.Now().UTC()).
+		Returning("*").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(c, errNoInvite)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "invite_failed",
+			Message: "Failed to decline invite",
+		})
+		return
+	}
+
+	h.publishInviteCancelled(invite, accountID, "declined")
+	c.JSON(http.StatusOK, gin.H{"message": "Invite declined"})
+}
+
+// AcceptInvite joins the party in the path as a player, taking the slot
+// the invite reserved. The usual joining rules still apply.
+func (h *Handler) AcceptInvite(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	partyID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	sc := getScope(c)
+	if _, err := h.findMembership(ctx, sc, accountID); err == nil {
+		c.JSON(http.StatusConflict, middleware.ErrorResponse{
+			Error:   "already_in_party",
+			Message: "You are already in a party. Leave first.",
+		})
+		return
+	}
+
+	exists, err := h.db.NewSelect().
+		Model((*models.PartyInvite)(nil)).
+		Where("party_id = ? AND account_id = ? AND tenant = ? AND game = ?", partyID, accountID, sc.tenant, sc.game).
+		Where("expires_at > ?", time.Now().UTC()).
+		Exists(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch invite",
+		})
+		return
+	}
+	if !exists {
+		writeError(c, errNoInvite)
+		return
+	}
+
+	party := new(models.Party)
+	err = h.db.NewSelect().
+		Model(party).
+		Relation("Members").
+		Where("id = ? AND archived_at IS NULL", partyID).
+		Scan(ctx)
+	if err != nil {
+		writeError(c, errNoInvite)
+		return
+	}
+	h.joinParty(c, party, accountID, models.MemberPlayer)
+}
+
+// publishInviteCancelled tells the party and the invited player that an
+// invite is gone.
+func (h *Handler) publishInviteCancelled(invite *models.PartyInvite, actorID uuid.UUID, reason string) {
+	h.publish(events.Event{
+		Tenant:    invite.Tenant,
+		Type:      events.MemberInviteCancelled,
+		PartyID:   invite.PartyID,
+		AccountID: invite.AccountID,
+		ActorID:   actorID,
+		Data:      gin.H{"reason": reason},
+	})
+}
@@ -0,0 +1,217 @@
+package parties
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	// defaultLockTTL applies to locks taken without a TTL, so a backend
+	// that never unlocks can't freeze a roster for good.
+	defaultLockTTL = 10 * time.Minute
+	// maxLockTTL bounds how long a lock may be held before it is renewed.
+	maxLockTTL = 24 * time.Hour
+)
+
+var (
+	errPartyLocked   = newOpError(http.StatusConflict, "party_locked", "Party is locked")
+	errPartyArchived = newOpError(http.StatusConflict, "party_archived", "Party has been merged or split")
+)
+
+// isLocked reports whether the party's roster is locked: nobody can join or
+// be kicked, though members may still leave. Locks past their TTL are
+// ignored and overwritten by the next lock.
+func isLocked(party *models.Party, now time.Time) bool {
+	return party.LockedBy != "" && (party.LockedUntil == nil || now.Before(*party.LockedUntil))
+}
+
+// recheckLock re-reads party's lock through db, inside the transaction
+// making a roster change, and returns errPartyLocked if a lock is now in
+// force that wasn't on the copy the caller checked. This catches locks
+// taken after the party was first read, while a backend acting under its
+// own lock carries on.
+func recheckLock(ctx context.Context, db bun.IDB, party *models.Party) error {
+	current := new(models.Party)
+	err := db.NewSelect().
+		Model(current).
+		Column("locked_by", "locked_until").
+		Where("id = ?", party.ID).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !isLocked(current, now) || (isLocked(party, now) && current.LockedBy == party.LockedBy) {
+		return nil
+	}
+	return errPartyLocked
+}
+
+// hideExpiredLock clears a lapsed lock from a party about to be returned,
+// so responses only ever show locks in force.
+func hideExpiredLock(party *models.Party) {
+	if party.LockedBy != "" && !isLocked(party, time.Now()) {
+		party.LockedBy = ""
+		party.LockedUntil = nil
+	}
+}
+
+// LockParty freezes a party's roster for the calling service.
+func (h *Handler) LockParty(c *gin.Context) {
+	ctx := c.Request.Context()
+	partyID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	var req struct {
+		TTL string `json:"ttl"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength != 0 {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "ttl must be a duration such as 10m",
+			})
+			return
+		}
+	}
+
+	caller, _ := tenants.CallerFrom(ctx)
+	party, opErr := h.lockParty(ctx, caller, partyID, ttl)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	c.JSON(http.StatusOK, party)
+}
+
+// UnlockParty releases a party's roster lock.
+func (h *Handler) UnlockParty(c *gin.Context) {
+	ctx := c.Request.Context()
+	partyID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	caller, _ := tenants.CallerFrom(ctx)
+	party, opErr := h.unlockParty(ctx, caller, partyID)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	c.JSON(http.StatusOK, party)
+}
+
+// lockParty locks the roster on behalf of caller until ttl has passed, or
+// defaultLockTTL if ttl is zero. Locking a party the caller already holds
+// renews the lock. Archived parties can't be locked.
+func (h *Handler) lockParty(ctx context.Context, caller *tenants.Caller, partyID uuid.UUID, ttl time.Duration) (*models.Party, *opError) {
+	if ttl < 0 || ttl > maxLockTTL {
+		return nil, newOpError(http.StatusBadRequest, "invalid_request", "ttl must be between 0 and "+maxLockTTL.String())
+	}
+	if ttl == 0 {
+		ttl = defaultLockTTL
+	}
+
+	now := time.Now().UTC()
+	until := now.Add(ttl)
+
+	// The holder check is part of the update so two services can't both
+	// take the lock.
+	res, err := h.db.NewUpdate().
+		Model((*models.Party)(nil)).
+		Set("locked_by = ?", caller.Name).
+		Set("locked_until = ?", until).
+		Where("id = ? AND tenant = ? AND archived_at IS NULL", partyID, caller.Tenant).
+		Where("(locked_by IS NULL OR locked_by = ? OR locked_until <= ?)", caller.Name, now).
+		Exec(ctx)
+	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "lock_failed", "Failed to lock party")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		party, err := h.lookupParty(ctx, caller.Tenant, partyID, "")
+		if err != nil {
+			return nil, err
+		}
+		if party.ArchivedAt != nil {
+			return nil, errPartyArchived
+		}
+		return nil, newOpError(http.StatusConflict, "already_locked", "Party is locked by another service")
+	}
+
+	party, opErr := h.lookupParty(ctx, caller.Tenant, partyID, "")
+	if opErr != nil {
+		return nil, opErr
+	}
+	h.publish(events.Event{
+		Tenant:    party.Tenant,
+		Type:      events.PartyLocked,
+		PartyID:   party.ID,
+		AccountID: party.OwnerID,
+		Data:      gin.H{"locked_by": party.LockedBy, "locked_until": party.LockedUntil},
+	})
+	return party, nil
+}
+
+// unlockParty releases the lock if caller holds it or has the admin scope.
+// Unlocking a party that isn't locked, or whose lock has lapsed, succeeds
+// without an event.
+func (h *Handler) unlockParty(ctx context.Context, caller *tenants.Caller, partyID uuid.UUID) (*models.Party, *opError) {
+	party, opErr := h.lookupParty(ctx, caller.Tenant, partyID, "")
+	if opErr != nil {
+		return nil, opErr
+	}
+	if party.LockedBy == "" {
+		return party, nil
+	}
+	if party.LockedBy != caller.Name && !caller.Allows(tenants.ScopeAdmin) {
+		return nil, newOpError(http.StatusConflict, "already_locked", "Party is locked by another service")
+	}
+
+	_, err := h.db.NewUpdate().
+		Model((*models.Party)(nil)).
+		Set("locked_by = NULL").
+		Set("locked_until = NULL").
+		Where("id = ? AND tenant = ? AND locked_by = ?", partyID, caller.Tenant, party.LockedBy).
+		Exec(ctx)
+	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "unlock_failed", "Failed to unlock party")
+	}
+	party.LockedBy = ""
+	party.LockedUntil = nil
+
+	h.publish(events.Event{
+		Tenant:    party.Tenant,
+		Type:      events.PartyUnlocked,
+		PartyID:   party.ID,
+		AccountID: party.OwnerID,
+		Data:      gin.H{"unlocked_by": caller.Name},
+	})
+	return party, nil
+}
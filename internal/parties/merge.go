@@ -0,0 +1,544 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// mergeProposalTTL is how long the target party's owner has to accept.
+const mergeProposalTTL = 10 * time.Minute
+
+var (
+	errNoMergeProposal = newOpError(http.StatusNotFound, "no_merge_proposal", "No pending merge proposal from that party")
+	errMergeTooLarge   = newOpError(http.StatusConflict, "party_full", "The merged party would be over its player or spectator limit")
+)
+
+// mergeProposals is the body of GET /parties/merge.
+type mergeProposals struct {
+	Outgoing *models.PartyMerge  `json:"outgoing,omitempty"`
+	Incoming []models.PartyMerge `json:"incoming"`
+}
+
+// ProposeMerge offers to move the caller's whole party into the party with
+// the given invite code. It replaces any earlier proposal from the party.
+func (h *Handler) ProposeMerge(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		InviteCode string `json:"invite_code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invite_code is required",
+		})
+		return
+	}
+	req.InviteCode = strings.TrimSpace(req.InviteCode)
+
+	sc := getScope(c)
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermMerge); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	// Invite codes are looked up under the same throttle as JoinParty.
+	if res := h.cfg.Limiter.Blocked(c, "join_invalid", h.invalidCodeLimit(c)); !res.Allowed {
+		ratelimit.Reject(c, res)
+		return
+	}
+	target := new(models.Party)
+	err = h.db.NewSelect().
+		Model(target).
+		Relation("Members").
+		Where("invite_code = ? COLLATE NOCASE AND tenant = ? AND game = ?", req.InviteCode, sc.tenant, sc.game).
+		Where("archived_at IS NULL").
+		Scan(ctx)
+	if err != nil {
+		h.cfg.Limiter.Allow(c, "join_invalid", h.invalidCodeLimit(c))
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "invalid_code",
+			Message: "Invalid invite code",
+		})
+		return
+	}
+
+	source, err := h.getPartyWithMembers(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	if source.ID == target.ID {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "A party can't merge into itself",
+		})
+		return
+	}
+	now := time.Now()
+	if isLocked(source, now) || isLocked(target, now) {
+		writeError(c, errPartyLocked)
+		return
+	}
+	if !mergeFits(source, target) {
+		writeError(c, errMergeTooLarge)
+		return
+	}
+
+	now = now.UTC()
+	proposal := &models.PartyMerge{
+		SourceID:   source.ID,
+		TargetID:   target.ID,
+		Tenant:     sc.tenant,
+		ProposedBy: accountID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(mergeProposalTTL),
+	}
+	_, err = h.db.NewInsert().
+		Model(proposal).
+		On("CONFLICT (source_id) DO UPDATE").
+		Set("target_id = EXCLUDED.target_id").
+		Set("proposed_by = EXCLUDED.proposed_by").
+		Set("created_at = EXCLUDED.created_at").
+		Set("expires_at = EXCLUDED.expires_at").
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "merge_failed",
+			Message: "Failed to propose merge",
+		})
+		return
+	}
+
+	h.publishMerge(events.PartyMergeProposed, proposal, accountID, gin.H{"members": len(source.Members), "expires_at": proposal.ExpiresAt})
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// GetMergeProposals lists the party's pending proposal and those waiting
+// on it.
+func (h *Handler) GetMergeProposals(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+
+	var proposals []models.PartyMerge
+	err = h.db.NewSelect().
+		Model(&proposals).
+		Where("source_id = ? OR target_id = ?", member.PartyID, member.PartyID).
+		Where("expires_at > ?", time.Now().UTC()).
+		OrderExpr("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch merge proposals",
+		})
+		return
+	}
+
+	out := mergeProposals{Incoming: []models.PartyMerge{}}
+	for i := range proposals {
+		if proposals[i].SourceID == member.PartyID {
+			out.Outgoing = &proposals[i]
+		} else {
+			out.Incoming = append(out.Incoming, proposals[i])
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CancelMerge withdraws the party's pending proposal.
+func (h *Handler) CancelMerge(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermMerge); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	proposal := new(models.PartyMerge)
+	err = h.db.NewDelete().
+		Model(proposal).
+		Where("source_id = ?", member.PartyID).
+		Returning("*").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "no_merge_proposal",
+			Message: "Your party has no pending merge proposal",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "merge_failed",
+			Message: "Failed to cancel merge",
+		})
+		return
+	}
+
+	h.publishMerge(events.PartyMergeCancelled, proposal, accountID, gin.H{"reason": "withdrawn"})
+	c.JSON(http.StatusOK, gin.H{"message": "Merge proposal withdrawn"})
+}
+
+// DeclineMerge turns down the proposal from the party in the path.
+func (h *Handler) DeclineMerge(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	sourceID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermMerge); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	proposal := new(models.PartyMerge)
+	err = h.db.NewDelete().
+		Model(proposal).
+		Where("source_id = ? AND target_id = ?", sourceID, member.PartyID).
+		Returning("*").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(c, errNoMergeProposal)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "merge_failed",
+			Message: "Failed to decline merge",
+		})
+		return
+	}
+
+	h.publishMerge(events.PartyMergeCancelled, proposal, accountID, gin.H{"reason": "declined"})
+	c.JSON(http.StatusOK, gin.H{"message": "Merge proposal declined"})
+}
+
+// AcceptMerge moves every member of the proposing party into the caller's
+// party and archives the proposing party.
+func (h *Handler) AcceptMerge(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	sourceID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermMerge); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	moved, dropped, opErr := h.mergeParties(ctx, sourceID, member.PartyID)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	h.publish(events.Event{
+		Tenant:    member.Tenant,
+		Type:      events.PartyMerged,
+		PartyID:   sourceID,
+		AccountID: accountID,
+		ActorID:   accountID,
+		Data:      gin.H{"merged_into": member.PartyID},
+	})
+	for _, id := range moved {
+		h.publish(events.Event{
+			Tenant:    member.Tenant,
+			Type:      events.MemberJoined,
+			PartyID:   member.PartyID,
+			AccountID: id,
+			ActorID:   accountID,
+			Data:      gin.H{"merged_from": sourceID},
+		})
+	}
+	for i := range dropped {
+		h.publishInviteCancelled(&dropped[i], accountID, "joined_elsewhere")
+	}
+
+	party, err := h.getPartyWithMembers(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, viewFor(party, accountID))
+}
+
+// mergeParties carries out a pending merge of sourceID into targetID and
+// returns the accounts that moved, along with their invites to other
+// parties, which joining uses up. Joining rules apply to each of them:
+// neither party may be locked, and bans and blocks in the target party
+// keep the whole merge from happening.
+func (h *Handler) mergeParties(ctx context.Context, sourceID, targetID uuid.UUID) ([]uuid.UUID, []models.PartyInvite, *opError) {
+	exists, err := h.db.NewSelect().
+		Model((*models.PartyMerge)(nil)).
+		Where("source_id = ? AND target_id = ? AND expires_at > ?", sourceID, targetID, time.Now().UTC()).
+		Exists(ctx)
+	if err != nil {
+		return nil, nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch merge proposal")
+	}
+	if !exists {
+		return nil, nil, errNoMergeProposal
+	}
+
+	source, err := h.getPartyWithMembers(ctx, sourceID)
+	if err != nil {
+		return nil, nil, errNoMergeProposal
+	}
+	target, err := h.getPartyWithMembers(ctx, targetID)
+	if err != nil {
+		return nil, nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch party")
+	}
+	now := time.Now()
+	if isLocked(source, now) || isLocked(target, now) {
+		return nil, nil, errPartyLocked
+	}
+
+	moved := memberIDs(source)
+	existing := memberIDs(target)
+	for _, id := range moved {
+		if err := h.checkBan(ctx, target.ID, id); err != nil {
+			return nil, nil, err
+		}
+		if err := h.checkBlocks(ctx, target.Tenant, id, existing); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Members move with a single UPDATE of party_id, so each account keeps
+	// its one row and the unique account index holds throughout. The bans
+	// and blocks above were checked for moved, so the source's roster must
+	// still match it.
+	var dropped []models.PartyInvite
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		for _, party := range []*models.Party{source, target} {
+			if err := recheckLock(ctx, tx, party); err != nil {
+				return err
+			}
+		}
+		var current []uuid.UUID
+		err := tx.NewSelect().
+			Model((*models.PartyMember)(nil)).
+			Column("account_id").
+			Where("party_id = ?", sourceID).
+			Scan(ctx, &current)
+		if err != nil {
+			return err
+		}
+		if len(current) != len(moved) {
+			return errRosterChanged
+		}
+		for _, id := range current {
+			if !slices.Contains(moved, id) {
+				return errRosterChanged
+			}
+		}
+
+		res, err := tx.NewDelete().
+			Model((*models.PartyMerge)(nil)).
+			Where("source_id = ? AND target_id = ?", sourceID, targetID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errNoMergeProposal
+		}
+
+		// The source's invites lapse with it, and the moving members'
+		// invites in the game are used up, as when joining.
+		now := time.Now().UTC()
+		err = tx.NewSelect().
+			Model(&dropped).
+			Where("tenant = ? AND game = ? AND account_id IN (?)", target.Tenant, target.Game, bun.In(moved)).
+			Where("party_id NOT IN (?) AND expires_at > ?", bun.In([]uuid.UUID{sourceID, targetID}), now).
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewDelete().
+			Model((*models.PartyInvite)(nil)).
+			Where("party_id = ? OR (tenant = ? AND game = ? AND account_id IN (?))", sourceID, target.Tenant, target.Game, bun.In(moved)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Player slots held by the target's invites count as taken.
+		for _, memberType := range []string{models.MemberPlayer, models.MemberSpectator} {
+			count, err := countMembers(ctx, tx, memberType, sourceID, targetID)
+			if err != nil {
+				return err
+			}
+			if memberType == models.MemberPlayer {
+				reserved, err := countReserved(ctx, tx, targetID, uuid.Nil)
+				if err != nil {
+					return err
+				}
+				count += reserved
+			}
+			if count > capacity(target, memberType) {
+				return errMergeTooLarge
+			}
+		}
+
+		res, err = tx.NewUpdate().
+			Model((*models.Party)(nil)).
+			Set("archived_at = ?", now).
+			Set("merged_into = ?", targetID).
+			Set("locked_by = NULL").
+			Set("locked_until = NULL").
+			Set("updated_at = ?", now).
+			Where("id = ? AND archived_at IS NULL", sourceID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errNoMergeProposal
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("party_id = ?", targetID).
+			Set("role = ?", models.RoleMember).
+			Set("group_id = NULL").
+			Where("party_id = ?", sourceID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.PartyGroup)(nil)).
+			Where("party_id = ?", sourceID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Proposals into the archived party can no longer be accepted.
+		_, err = tx.NewDelete().
+			Model((*models.PartyMerge)(nil)).
+			Where("target_id = ?", sourceID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Party)(nil)).
+			Set("updated_at = ?", now).
+			Where("id = ?", targetID).
+			Exec(ctx)
+		return err
+	})
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		return nil, nil, opErr
+	}
+	if err != nil {
+		return nil, nil, newOpError(http.StatusInternalServerError, "merge_failed", "Failed to merge parties")
+	}
+	return moved, dropped, nil
+}
+
+// mergeFits reports whether the target has room for the source's players
+// and spectators.
+func mergeFits(source, target *models.Party) bool {
+	for _, memberType := range []string{models.MemberPlayer, models.MemberSpectator} {
+		if countType(source, memberType)+countType(target, memberType) > capacity(target, memberType) {
+			return false
+		}
+	}
+	return true
+}
+
+// publishMerge tells both parties about a change to a proposal.
+func (h *Handler) publishMerge(eventType string, proposal *models.PartyMerge, actorID uuid.UUID, data gin.H) {
+	data["source_id"] = proposal.SourceID
+	data["target_id"] = proposal.TargetID
+	for _, partyID := range []uuid.UUID{proposal.SourceID, proposal.TargetID} {
+		h.publish(events.Event{
+			Tenant:    proposal.Tenant,
+			Type:      eventType,
+			PartyID:   partyID,
+			AccountID: actorID,
+			ActorID:   actorID,
+			Data:      data,
+		})
+	}
+}
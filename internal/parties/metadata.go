@@ -0,0 +1,174 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+)
+
+type metadataRequest struct {
+	Metadata models.Metadata `json:"metadata" binding:"required"`
+}
+
+// UpdatePartyMetadata merges keys into the party's metadata. A key set to
+// null is removed.
+func (h *Handler) UpdatePartyMetadata(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req metadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "metadata is required",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, err := h.authorizeMember(ctx, member, PermMetadata); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	var metadata models.Metadata
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		party := new(models.Party)
+		if err := tx.NewSelect().Model(party).Where("p.id = ?", member.PartyID).Scan(ctx); err != nil {
+			return err
+		}
+		var err error
+		metadata, err = mergeMetadata(party.Metadata, req.Metadata, models.MaxPartyMetadataBytes)
+		if err != nil {
+			return err
+		}
+		if mode, ok := metadata["mode"]; ok && !h.gameConfig(party.Game).allowsMode(mode) {
+			return newOpError(http.StatusBadRequest, "invalid_mode", "That mode isn't available in this game")
+		}
+		_, err = tx.NewUpdate().
+			Model((*models.Party)(nil)).
+			Set("metadata = ?", metadata).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("id = ?", member.PartyID).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		writeError(c, metadataError(err))
+		return
+	}
+
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.PartyMetadataChanged, PartyID: member.PartyID, AccountID: accountID, ActorID: accountID, Data: metadata})
+	c.JSON(http.StatusOK, gin.H{"metadata": metadata})
+}
+
+// UpdateMemberMetadata merges keys into the caller's own member metadata.
+// A key set to null is removed.
+func (h *Handler) UpdateMemberMetadata(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req metadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "metadata is required",
+		})
+		return
+	}
+
+	var (
+		member   *models.PartyMember
+		metadata models.Metadata
+	)
+	sc := getScope(c)
+	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		member = new(models.PartyMember)
+		err := tx.NewSelect().
+			Model(member).
+			Where("tenant = ? AND game = ? AND account_id = ?", sc.tenant, sc.game, accountID).
+			Scan(ctx)
+		if err != nil {
+			return errNotInParty
+		}
+		metadata, err = mergeMetadata(member.Metadata, req.Metadata, models.MaxMemberMetadataBytes)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("metadata = ?", metadata).
+			Where("party_id = ? AND account_id = ?", member.PartyID, accountID).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		writeError(c, metadataError(err))
+		return
+	}
+
+	h.publish(events.Event{Tenant: member.Tenant, Type: events.MemberMetadataChanged, PartyID: member.PartyID, AccountID: accountID, ActorID: accountID, Data: metadata})
+	c.JSON(http.StatusOK, gin.H{"metadata": metadata})
+}
+
+// mergeMetadata applies patch to current and checks the result against the
+// key and size limits.
+func mergeMetadata(current, patch models.Metadata, maxBytes int) (models.Metadata, error) {
+	merged := make(models.Metadata, len(current)+len(patch))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		if k == "" || len(k) > models.MaxMetadataKeyLength {
+			return nil, newOpError(http.StatusBadRequest, "invalid_metadata",
+				"Metadata keys must be between 1 and "+strconv.Itoa(models.MaxMetadataKeyLength)+" bytes")
+		}
+		if v == nil {
+			delete(merged, k)
+		} else {
+			merged[k] = v
+		}
+	}
+
+	if len(merged) > models.MaxMetadataKeys {
+		return nil, newOpError(http.StatusRequestEntityTooLarge, "metadata_too_large",
+			"Metadata can hold at most "+strconv.Itoa(models.MaxMetadataKeys)+" keys")
+	}
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return nil, newOpError(http.StatusBadRequest, "invalid_metadata", "Metadata must be valid JSON")
+	}
+	if len(encoded) > maxBytes {
+		return nil, newOpError(http.StatusRequestEntityTooLarge, "metadata_too_large",
+			"Metadata can be at most "+strconv.Itoa(maxBytes)+" bytes")
+	}
+	return merged, nil
+}
+
+func metadataError(err error) *opError {
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		return opErr
+	}
+	return newOpError(http.StatusInternalServerError, "update_failed", "Failed to update metadata")
+}
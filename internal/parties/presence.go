@@ -0,0 +1,279 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// PresenceConfig controls how presence is derived from heartbeats and when
+// silent members are removed. A member is away once AwayAfter passes without
+// a heartbeat, offline after OfflineAfter, and removed from the party after
+// a further OfflineGrace. Zero values disable the corresponding step.
+// Removal is off by default, since clients that never heartbeat would
+// otherwise lose their seat.
+type PresenceConfig struct {
+	AwayAfter    time.Duration
+	OfflineAfter time.Duration
+	OfflineGrace time.Duration
+	ReapInterval time.Duration
+}
+
+var DefaultPresenceConfig = PresenceConfig{
+	AwayAfter:    time.Minute,
+	OfflineAfter: 3 * time.Minute,
+	ReapInterval: 30 * time.Second,
+}
+
+// Heartbeat keeps the caller online. An optional status of "away" lets a
+// client report an idle player while it is still connected.
+func (h *Handler) Heartbeat(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid heartbeat body",
+		})
+		return
+	}
+	if req.Status != "" && req.Status != models.PresenceOnline && req.Status != models.PresenceAway {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "status must be online or away",
+		})
+		return
+	}
+
+	presence, err := h.heartbeat(ctx, getScope(c), accountID, req.Status)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"presence": presence})
+}
+
+// heartbeat records that the account was just seen. An empty status keeps
+// the previously reported one.
+func (h *Handler) heartbeat(ctx context.Context, sc scope, accountID uuid.UUID, status string) (string, *opError) {
+	member, err := h.findMembership(ctx, sc, accountID)
+	if err != nil {
+		return "", errNotInParty
+	}
+
+	now := time.Now().UTC()
+	before := h.presenceOf(member, now)
+
+	q := h.db.NewUpdate().
+		Model((*models.PartyMember)(nil)).
+		Set("last_seen_at = ?", now).
+		Where("party_id = ? AND account_id = ?", member.PartyID, accountID)
+	if status != "" {
+		q = q.Set("presence = ?", status)
+		member.Presence = status
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return "", newOpError(http.StatusInternalServerError, "heartbeat_failed", "Failed to record heartbeat")
+	}
+	member.LastSeenAt = now
+
+	after := h.presenceOf(member, now)
+	if after != before {
+		h.publish(events.Event{
+			Tenant:    member.Tenant,
+			Type:      events.MemberPresence,
+			PartyID:   member.PartyID,
+			AccountID: accountID,
+			ActorID:   accountID,
+			Data:      gin.H{"presence": after},
+		})
+	}
+	return after, nil
+}
+
+// presenceOf derives a member's presence from the status they last reported
+// and how long ago they were seen.
+func (h *Handler) presenceOf(m *models.PartyMember, now time.Time) string {
+	lastSeen := m.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = m.JoinedAt
+	}
+	idle := now.Sub(lastSeen)
+
+	cfg := h.cfg.Presence
+	switch {
+	case cfg.OfflineAfter > 0 && idle >= cfg.OfflineAfter:
+		return models.PresenceOffline
+	case cfg.AwayAfter > 0 && idle >= cfg.AwayAfter:
+		return models.PresenceAway
+	case m.Presence == models.PresenceAway:
+		return models.PresenceAway
+	}
+	return models.PresenceOnline
+}
+
+func (h *Handler) applyPresence(party *models.Party) {
+	now := time.Now().UTC()
+	for i := range party.Members {
+		party.Members[i].Presence = h.presenceOf(&party.Members[i], now)
+	}
+}
+
+// RunPresenceReaper removes members who stayed offline past the grace
+// period, until ctx is done.
+func (h *Handler) RunPresenceReaper(ctx context.Context) {
+	cfg := h.cfg.Presence
+	if cfg.OfflineAfter <= 0 || cfg.OfflineGrace <= 0 || cfg.ReapInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.ReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.reapOfflineMembers(ctx); err != nil {
+				log.Printf("Presence reaper failed: %v", err)
+			}
+		}
+	}
+}
+
+func (h *Handler) reapOfflineMembers(ctx context.Context) error {
+	cfg := h.cfg.Presence
+	now := time.Now().UTC()
+	cutoff := now.Add(-(cfg.OfflineAfter + cfg.OfflineGrace))
+
+	// Locked rosters are left alone; removeOfflineMember checks again.
+	var stale []models.PartyMember
+	err := h.db.NewSelect().
+		Model(&stale).
+		Where("COALESCE(last_seen_at, joined_at) < ?", cutoff).
+		Where("party_id NOT IN (SELECT id FROM parties WHERE locked_by IS NOT NULL AND (locked_until IS NULL OR locked_until > ?))", now).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range stale {
+		if err := h.removeOfflineMember(ctx, m, cutoff); err != nil {
+			log.Printf("Failed to remove offline member %s from party %s: %v", m.AccountID, m.PartyID, err)
+		}
+	}
+	return nil
+}
+
+// errStillActive stops a removal whose member was seen, or whose party was
+// locked, after the reaper's scan.
+var errStillActive = errors.New("member active again")
+
+// removeOfflineMember drops a member who has been offline too long. An
+// offline owner hands the party to the most recently active player who
+// isn't due for removal; if there is none the party is disbanded. The
+// member is skipped if they heartbeat or the roster is locked before the
+// removal commits.
+func (h *Handler) removeOfflineMember(ctx context.Context, m models.PartyMember, cutoff time.Time) error {
+	var successor *models.PartyMember
+	abandoned := false
+	err := h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if err := recheckLock(ctx, tx, &models.Party{ID: m.PartyID}); err != nil {
+			if errors.Is(err, errPartyLocked) {
+				return errStillActive
+			}
+			return err
+		}
+
+		res, err := tx.NewDelete().
+			Model((*models.PartyMember)(nil)).
+			Where("party_id = ? AND account_id = ?", m.PartyID, m.AccountID).
+			Where("COALESCE(last_seen_at, joined_at) < ?", cutoff).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errStillActive
+		}
+		if m.Role != models.RoleOwner {
+			return nil
+		}
+
+		successor = new(models.PartyMember)
+		err = tx.NewSelect().
+			Model(successor).
+			Where("party_id = ? AND type = ?", m.PartyID, models.MemberPlayer).
+			Where("COALESCE(last_seen_at, joined_at) >= ?", cutoff).
+			OrderExpr("COALESCE(last_seen_at, joined_at) DESC").
+			Limit(1).
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			abandoned = true
+			return deleteParty(ctx, tx, m.PartyID)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("role = ?", models.RoleOwner).
+			Where("party_id = ? AND account_id = ?", m.PartyID, successor.AccountID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Party)(nil)).
+			Set("owner_id = ?", successor.AccountID).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("id = ?", m.PartyID).
+			Exec(ctx)
+		return err
+	})
+	if errors.Is(err, errStillActive) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if abandoned {
+		h.publishDisbanded(m.Tenant, m.PartyID, m.AccountID, DisbandAbandoned)
+		return nil
+	}
+	h.publish(events.Event{
+		Tenant:    m.Tenant,
+		Type:      events.MemberLeft,
+		PartyID:   m.PartyID,
+		AccountID: m.AccountID,
+		ActorID:   m.AccountID,
+		Data:      gin.H{"reason": "offline"},
+	})
+	if successor != nil {
+		h.publish(events.Event{Tenant: m.Tenant, Type: events.PartyOwnerChanged, PartyID: m.PartyID, AccountID: successor.AccountID, ActorID: m.AccountID})
+	}
+	return nil
+}
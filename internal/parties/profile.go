@@ -0,0 +1,120 @@
+package parties
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Emblems are IDs of artwork the game client ships, not uploaded images.
+var emblemPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// UpdateProfile sets the party's display name, description and emblem.
+// Omitted fields are left unchanged; an empty string clears a field.
+func (h *Handler) UpdateProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Emblem      *string `json:"emblem"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Name == nil && req.Description == nil && req.Emblem == nil) {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "name, description or emblem is required",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	party, opErr := h.authorizeMember(ctx, member, PermSettings)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	if req.Name != nil {
+		name, err := h.checkText(*req.Name, "name", models.MaxNameLength)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		party.Name = name
+	}
+	if req.Description != nil {
+		description, err := h.checkText(*req.Description, "description", models.MaxDescriptionLength)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		party.Description = description
+	}
+	if req.Emblem != nil {
+		emblem := strings.TrimSpace(*req.Emblem)
+		if emblem != "" && (len(emblem) > models.MaxEmblemLength || !emblemPattern.MatchString(emblem)) {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_emblem",
+				Message: "Emblem must be up to " + strconv.Itoa(models.MaxEmblemLength) + " lowercase letters, digits, - or _",
+			})
+			return
+		}
+		party.Emblem = emblem
+	}
+
+	party.UpdatedAt = time.Now().UTC()
+	_, err = h.db.NewUpdate().
+		Model(party).
+		Column("name", "description", "emblem", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "update_failed",
+			Message: "Failed to update party",
+		})
+		return
+	}
+
+	profile := gin.H{"name": party.Name, "description": party.Description, "emblem": party.Emblem}
+	h.publish(events.Event{Tenant: party.Tenant, Type: events.PartyProfileChanged, PartyID: party.ID, AccountID: accountID, ActorID: accountID, Data: profile})
+	c.JSON(http.StatusOK, profile)
+}
+
+// checkText trims a player-written field and checks its length, characters
+// and the configured word filter.
+func (h *Handler) checkText(text, field string, maxLength int) (string, *opError) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > maxLength {
+		return "", newOpError(http.StatusBadRequest, "invalid_"+field,
+			"The "+field+" can be at most "+strconv.Itoa(maxLength)+" characters")
+	}
+	for _, r := range text {
+		if unicode.IsControl(r) {
+			return "", newOpError(http.StatusBadRequest, "invalid_"+field, "The "+field+" can't contain control characters")
+		}
+	}
+	if h.cfg.TextFilter != nil {
+		if _, found := h.cfg.TextFilter.Match(text); found {
+			return "", newOpError(http.StatusBadRequest, "name_rejected", "The "+field+" contains a word that isn't allowed")
+		}
+	}
+	return text, nil
+}
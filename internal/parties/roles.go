@@ -0,0 +1,95 @@
+package parties
+
+import (
+	"net/http"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) PromoteMember(c *gin.Context) {
+	h.setRole(c, models.RoleMember, models.RoleModerator)
+}
+
+func (h *Handler) DemoteMember(c *gin.Context) {
+	h.setRole(c, models.RoleModerator, models.RoleMember)
+}
+
+// setRole moves a member of the caller's party from one role to another.
+func (h *Handler) setRole(c *gin.Context, from, to string) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AccountID uuid.UUID `json:"account_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "account_id is required",
+		})
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, err := h.authorizeMember(ctx, member, PermManageRoles); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	target, err := h.findMember(ctx, member.PartyID, req.AccountID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, middleware.ErrorResponse{
+			Error:   "not_in_party",
+			Message: "That player is not in your party",
+		})
+		return
+	}
+
+	if target.Role != from {
+		c.JSON(http.StatusConflict, middleware.ErrorResponse{
+			Error:   "invalid_role",
+			Message: "That player is not a " + from,
+		})
+		return
+	}
+
+	_, err = h.db.NewUpdate().
+		Model((*models.PartyMember)(nil)).
+		Set("role = ?", to).
+		Where("party_id = ? AND account_id = ?", member.PartyID, req.AccountID).
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "role_change_failed",
+			Message: "Failed to change role",
+		})
+		return
+	}
+
+	h.publish(events.Event{
+		Tenant:    member.Tenant,
+		Type:      events.MemberRoleChanged,
+		PartyID:   member.PartyID,
+		AccountID: req.AccountID,
+		ActorID:   accountID,
+		Data:      gin.H{"role": to},
+	})
+
+	party, err := h.getPartyWithMembers(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"role": to})
+		return
+	}
+	c.JSON(http.StatusOK, viewFor(party, accountID))
+}
@@ -0,0 +1,27 @@
+package parties
+
+import (
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/gin-gonic/gin"
+)
+
+// scope is the tenant and game namespace a request acts in. Every lookup
+// that starts from a caller-supplied account ID, party ID or invite code
+// filters on it; rows reached through a scoped membership inherit it.
+type scope struct {
+	tenant string
+	game   string
+}
+
+func getScope(c *gin.Context) scope {
+	return scope{tenant: getTenant(c), game: getGame(c)}
+}
+
+// getTenant returns the tenant resolved by JWT or service authentication.
+func getTenant(c *gin.Context) string {
+	if tenant := c.GetString(auth.TenantKey); tenant != "" {
+		return tenant
+	}
+	return tenants.Default
+}
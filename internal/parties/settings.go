@@ -0,0 +1,119 @@
+package parties
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type settingsResponse struct {
+	Permissions models.PermissionPolicy `json:"permissions"`
+}
+
+// GetSettings returns the caller's party settings. Every permission is
+// listed with the role currently required for it.
+func (h *Handler) GetSettings(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+
+	party, err := h.getParty(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settingsResponse{Permissions: effectivePolicy(party)})
+}
+
+// UpdateSettings merges the given permission overrides into the party's
+// policy. Setting a permission back to its default removes the override.
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req settingsResponse
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Permissions) == 0 {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "permissions is required",
+		})
+		return
+	}
+	for perm, role := range req.Permissions {
+		if !isConfigurable(Permission(perm)) {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_permission",
+				Message: "Unknown or fixed permission: " + perm,
+			})
+			return
+		}
+		if _, ok := roleRank[role]; !ok {
+			c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+				Error:   "invalid_role",
+				Message: "Role must be owner, moderator or member",
+			})
+			return
+		}
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	party, opErr := h.authorizeMember(ctx, member, PermSettings)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+
+	overrides := make(models.PermissionPolicy, len(party.Permissions)+len(req.Permissions))
+	for perm, role := range party.Permissions {
+		overrides[perm] = role
+	}
+	for perm, role := range req.Permissions {
+		if role == defaultPolicy[Permission(perm)] {
+			delete(overrides, perm)
+		} else {
+			overrides[perm] = role
+		}
+	}
+
+	party.Permissions = overrides
+	party.UpdatedAt = time.Now().UTC()
+	_, err = h.db.NewUpdate().
+		Model(party).
+		Column("permissions", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "update_failed",
+			Message: "Failed to update settings",
+		})
+		return
+	}
+
+	resp := settingsResponse{Permissions: effectivePolicy(party)}
+	h.publish(events.Event{Tenant: party.Tenant, Type: events.PartySettingsChanged, PartyID: party.ID, AccountID: accountID, ActorID: accountID, Data: resp})
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,156 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	errPartyFull      = newOpError(http.StatusConflict, "party_full", "Party is full")
+	errSpectatorsFull = newOpError(http.StatusConflict, "spectators_full", "No spectator slots are free")
+)
+
+// capacity is how many members of memberType the party holds.
+func capacity(party *models.Party, memberType string) int {
+	if memberType == models.MemberSpectator {
+		return party.SpectatorSlots
+	}
+	return party.MaxSize
+}
+
+// errFull is the error for a party with no free slot of memberType.
+func errFull(memberType string) *opError {
+	if memberType == models.MemberSpectator {
+		return errSpectatorsFull
+	}
+	return errPartyFull
+}
+
+// countType counts the loaded members of memberType.
+func countType(party *models.Party, memberType string) int {
+	n := 0
+	for _, m := range party.Members {
+		if m.Type == memberType {
+			n++
+		}
+	}
+	return n
+}
+
+// countMembers counts the members of memberType across partyIDs. Capacity
+// checks call it inside the transaction that adds the members.
+func countMembers(ctx context.Context, db bun.IDB, memberType string, partyIDs ...uuid.UUID) (int, error) {
+	return db.NewSelect().
+		Model((*models.PartyMember)(nil)).
+		Where("party_id IN (?) AND type = ?", bun.In(partyIDs), memberType).
+		Count(ctx)
+}
+
+// Spectate makes the caller a spectator of their party.
+func (h *Handler) Spectate(c *gin.Context) {
+	h.setType(c, models.MemberSpectator)
+}
+
+// Play makes the caller a player of their party.
+func (h *Handler) Play(c *gin.Context) {
+	h.setType(c, models.MemberPlayer)
+}
+
+// setType switches the caller to memberType if the party has a free slot
+// of that type; player slots held by invites aren't free. Spectators leave
+// their sub-group.
+func (h *Handler) setType(c *gin.Context, memberType string) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if member.Type == memberType {
+		c.JSON(http.StatusConflict, middleware.ErrorResponse{
+			Error:   "invalid_type",
+			Message: "You are already a " + memberType,
+		})
+		return
+	}
+	party, err := h.getParty(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	if isLocked(party, time.Now()) {
+		writeError(c, errPartyLocked)
+		return
+	}
+
+	err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if err := recheckLock(ctx, tx, party); err != nil {
+			return err
+		}
+		count, err := countTaken(ctx, tx, party.ID, memberType, accountID)
+		if err != nil {
+			return err
+		}
+		if count >= capacity(party, memberType) {
+			return errFull(memberType)
+		}
+		q := tx.NewUpdate().
+			Model((*models.PartyMember)(nil)).
+			Set("type = ?", memberType).
+			Where("party_id = ? AND account_id = ?", party.ID, accountID)
+		if memberType == models.MemberSpectator {
+			q = q.Set("group_id = NULL")
+		}
+		_, err = q.Exec(ctx)
+		return err
+	})
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		writeError(c, opErr)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "type_change_failed",
+			Message: "Failed to switch",
+		})
+		return
+	}
+
+	h.publish(events.Event{
+		Tenant:    party.Tenant,
+		Type:      events.MemberTypeChanged,
+		PartyID:   party.ID,
+		AccountID: accountID,
+		ActorID:   accountID,
+		Data:      gin.H{"type": memberType},
+	})
+
+	party, err = h.getPartyWithMembers(ctx, party.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, viewFor(party, accountID))
+}
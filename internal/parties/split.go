@@ -0,0 +1,302 @@
+package parties
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var errRosterChanged = newOpError(http.StatusConflict, "roster_changed", "The party's members changed; fetch it and try again")
+
+// splitGroup is one of the parties a split creates: its members, one of
+// whom becomes its owner.
+type splitGroup struct {
+	OwnerID uuid.UUID   `json:"owner_id"`
+	Members []uuid.UUID `json:"members"`
+}
+
+// SplitParty divides the caller's party into new parties, one per group.
+func (h *Handler) SplitParty(c *gin.Context) {
+	ctx := c.Request.Context()
+	accountID, ok := getAccountID(c)
+	if !ok {
+		return
+	}
+	groups, ok := bindSplit(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.findMembership(ctx, getScope(c), accountID)
+	if err != nil {
+		writeError(c, errNotInParty)
+		return
+	}
+	if _, opErr := h.authorizeMember(ctx, member, PermSplit); opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	party, err := h.getPartyWithMembers(ctx, member.PartyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Error:   "fetch_failed",
+			Message: "Failed to fetch party",
+		})
+		return
+	}
+	if isLocked(party, time.Now()) {
+		writeError(c, errPartyLocked)
+		return
+	}
+
+	children, opErr := h.splitParty(ctx, party, groups, accountID, "")
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	for _, child := range children {
+		viewFor(child, accountID)
+	}
+	c.JSON(http.StatusCreated, gin.H{"parties": children})
+}
+
+// SplitPartyByID divides a party on behalf of a service, such as a
+// matchmaker assigning teams. A locked party can only be split by the
+// credential holding the lock, or one with the admin scope.
+func (h *Handler) SplitPartyByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	partyID, err := uuid.Parse(c.Param("partyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid party ID",
+		})
+		return
+	}
+	groups, ok := bindSplit(c)
+	if !ok {
+		return
+	}
+
+	caller, _ := tenants.CallerFrom(ctx)
+	party, opErr := h.lookupParty(ctx, caller.Tenant, partyID, "")
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	if isLocked(party, time.Now()) && party.LockedBy != caller.Name && !caller.Allows(tenants.ScopeAdmin) {
+		writeError(c, errPartyLocked)
+		return
+	}
+
+	children, opErr := h.splitParty(ctx, party, groups, uuid.Nil, caller.Name)
+	if opErr != nil {
+		writeError(c, opErr)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"parties": children})
+}
+
+func bindSplit(c *gin.Context) ([]splitGroup, bool) {
+	var req struct {
+		Groups []splitGroup `json:"groups"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Groups) < 2 {
+		c.JSON(http.StatusBadRequest, middleware.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "groups must list at least two groups",
+		})
+		return nil, false
+	}
+	return req.Groups, true
+}
+
+// splitParty moves the parent's members into one new party per group and
+// archives the parent. The groups must partition the parent's members, and
+// each group's owner must be one of its members. Everything happens in one
+// transaction, which fails with errRosterChanged if someone joined or left
+// since the parent was read. A backend split has no actor; splitBy names
+// its credential instead.
+func (h *Handler) splitParty(ctx context.Context, parent *models.Party, groups []splitGroup, actorID uuid.UUID, splitBy string) ([]*models.Party, *opError) {
+	inParent := make(map[uuid.UUID]bool, len(parent.Members))
+	for _, m := range parent.Members {
+		inParent[m.AccountID] = true
+	}
+	assigned := make(map[uuid.UUID]bool, len(parent.Members))
+	for _, g := range groups {
+		hasOwner := false
+		for _, id := range g.Members {
+			if !inParent[id] {
+				return nil, newOpError(http.StatusBadRequest, "invalid_partition", "Every account must be a member of the party")
+			}
+			if assigned[id] {
+				return nil, newOpError(http.StatusBadRequest, "invalid_partition", "A member can only be in one group")
+			}
+			assigned[id] = true
+			hasOwner = hasOwner || id == g.OwnerID
+		}
+		if !hasOwner {
+			return nil, newOpError(http.StatusBadRequest, "invalid_partition", "Each group's owner_id must be one of its members")
+		}
+	}
+	if len(assigned) != len(parent.Members) {
+		return nil, newOpError(http.StatusBadRequest, "invalid_partition", "Every member must be in a group")
+	}
+
+	now := time.Now().UTC()
+	children := make([]*models.Party, len(groups))
+	for i, g := range groups {
+		children[i] = &models.Party{
+			ID:             uuid.New(),
+			OwnerID:        g.OwnerID,
+			Tenant:         parent.Tenant,
+			Game:           parent.Game,
+			MaxSize:        parent.MaxSize,
+			SpectatorSlots: parent.SpectatorSlots,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			SplitFrom:      &parent.ID,
+		}
+	}
+
+	// Retry with fresh codes if a generated one is already taken
+	var err error
+	for attempt := 0; attempt < maxInviteCodeAttempts; attempt++ {
+		for _, child := range children {
+			child.InviteCode = h.generateInviteCode()
+		}
+		err = h.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+			if err := recheckLock(ctx, tx, parent); err != nil {
+				return err
+			}
+			var current []uuid.UUID
+			err := tx.NewSelect().
+				Model((*models.PartyMember)(nil)).
+				Column("account_id").
+				Where("party_id = ?", parent.ID).
+				Scan(ctx, &current)
+			if err != nil {
+				return err
+			}
+			if len(current) != len(assigned) {
+				return errRosterChanged
+			}
+			for _, id := range current {
+				if !assigned[id] {
+					return errRosterChanged
+				}
+			}
+
+			res, err := tx.NewUpdate().
+				Model((*models.Party)(nil)).
+				Set("archived_at = ?", now).
+				Set("locked_by = NULL").
+				Set("locked_until = NULL").
+				Set("updated_at = ?", now).
+				Where("id = ? AND archived_at IS NULL", parent.ID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				return errRosterChanged
+			}
+
+			for i, child := range children {
+				if _, err := tx.NewInsert().Model(child).Exec(ctx); err != nil {
+					return err
+				}
+				_, err = tx.NewUpdate().
+					Model((*models.PartyMember)(nil)).
+					Set("party_id = ?", child.ID).
+					Set("role = CASE WHEN account_id = ? THEN ? ELSE ? END", child.OwnerID, models.RoleOwner, models.RoleMember).
+					Set("group_id = NULL").
+					Where("party_id = ? AND account_id IN (?)", parent.ID, bun.In(groups[i].Members)).
+					Exec(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = tx.NewDelete().
+				Model((*models.PartyGroup)(nil)).
+				Where("party_id = ?", parent.ID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.NewDelete().
+				Model((*models.PartyInvite)(nil)).
+				Where("party_id = ?", parent.ID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.NewDelete().
+				Model((*models.PartyMerge)(nil)).
+				Where("source_id = ? OR target_id = ?", parent.ID, parent.ID).
+				Exec(ctx)
+			return err
+		})
+		if !isInviteCodeCollision(err) {
+			break
+		}
+	}
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		return nil, opErr
+	}
+	if err != nil {
+		return nil, newOpError(http.StatusInternalServerError, "split_failed", "Failed to split party")
+	}
+
+	childIDs := make([]uuid.UUID, len(children))
+	for i, child := range children {
+		childIDs[i] = child.ID
+	}
+	splitData := gin.H{"parties": childIDs}
+	if splitBy != "" {
+		splitData["split_by"] = splitBy
+	}
+	h.publish(events.Event{
+		Tenant:    parent.Tenant,
+		Type:      events.PartySplit,
+		PartyID:   parent.ID,
+		AccountID: parent.OwnerID,
+		ActorID:   actorID,
+		Data:      splitData,
+	})
+	for i, child := range children {
+		data := gin.H{"split_from": parent.ID}
+		if splitBy != "" {
+			data["split_by"] = splitBy
+		}
+		h.publish(events.Event{Tenant: child.Tenant, Type: events.PartyCreated, PartyID: child.ID, AccountID: child.OwnerID, ActorID: actorID, Data: data})
+		for _, id := range groups[i].Members {
+			if id != child.OwnerID {
+				h.publish(events.Event{Tenant: child.Tenant, Type: events.MemberJoined, PartyID: child.ID, AccountID: id, ActorID: actorID, Data: data})
+			}
+		}
+	}
+
+	for i, child := range children {
+		full, err := h.getPartyWithMembers(ctx, child.ID)
+		if err != nil {
+			return nil, newOpError(http.StatusInternalServerError, "fetch_failed", "Failed to fetch party")
+		}
+		children[i] = full
+	}
+	return children, nil
+}
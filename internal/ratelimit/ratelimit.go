@@ -0,0 +1,189 @@
+// Package ratelimit provides token-bucket rate limiting for Hand's HTTP
+// routes. Buckets are keyed per account and per client IP and live in a
+// pluggable Store so the in-memory backend can be swapped for a shared one
+// when Hand runs as more than one replica.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Limit describes a token bucket holding up to Burst tokens that refills
+// completely over Period. A Limit with Burst <= 0 disables limiting.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled reports whether the limit should be enforced.
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// ParseLimit parses a limit written as "<burst>/<period>", e.g. "10/1m".
+// "off" and "0" disable the limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+
+	burst, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, expected <burst>/<period>", s)
+	}
+	n, err := strconv.Atoi(burst)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("invalid burst in limit %q", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid period in limit %q", s)
+	}
+	return Limit{Burst: n, Period: d}, nil
+}
+
+// ParseLimits parses a comma-separated list of "<name>=<limit>" pairs,
+// e.g. "join=20/1m,invite=5/1m".
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected <name>=<limit>", pair)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits, nil
+}
+
+// Result is the outcome of a bucket check.
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Store is a token-bucket backend.
+type Store interface {
+	// Take consumes one token from the bucket at key.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Peek reports whether the bucket at key has a token left without
+	// consuming it.
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter applies limits to requests using a Store.
+type Limiter struct {
+	store Store
+}
+
+func New(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Allow consumes a token from both the caller's account and IP buckets for
+// name. The account bucket is skipped on routes without JWT auth. Both
+// buckets are checked first, so a request one of them rejects costs the
+// other nothing.
+func (l *Limiter) Allow(c *gin.Context, name string, limit Limit) Result {
+	if res := l.check(c, name, limit, l.store.Peek); !res.Allowed {
+		return res
+	}
+	return l.check(c, name, limit, l.store.Take)
+}
+
+// Blocked reports whether either of the caller's buckets for name is empty,
+// without consuming a token. Used to gate requests that are only charged
+// when they fail.
+func (l *Limiter) Blocked(c *gin.Context, name string, limit Limit) Result {
+	return l.check(c, name, limit, l.store.Peek)
+}
+
+func (l *Limiter) check(c *gin.Context, name string, limit Limit, fn func(context.Context, string, Limit) (Result, error)) Result {
+	if l == nil || !limit.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	ctx := c.Request.Context()
+	for _, key := range keys(c, name) {
+		res, err := fn(ctx, key, limit)
+		if err != nil {
+			// Fail open: a broken limiter backend must not take the API down.
+			log.Printf("Rate limiter error for %s: %v", key, err)
+			continue
+		}
+		if !res.Allowed {
+			return res
+		}
+	}
+	return Result{Allowed: true}
+}
+
+// Middleware rejects requests that exceed limit for the named route.
+func (l *Limiter) Middleware(name string, limit Limit) gin.HandlerFunc {
+	return l.MiddlewareFunc(name, func(*gin.Context) Limit { return limit })
+}
+
+// MiddlewareFunc is Middleware with the limit picked per request, e.g. by
+// the caller's tenant.
+func (l *Limiter) MiddlewareFunc(name string, limit func(*gin.Context) Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if res := l.Allow(c, name, limit(c)); !res.Allowed {
+			Reject(c, res)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Reject aborts the request with 429 and a Retry-After header.
+func Reject(c *gin.Context, res Result) {
+	seconds := int(math.Ceil(res.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, middleware.ErrorResponse{
+		Error:   "rate_limited",
+		Message: "Too many requests. Try again later.",
+	})
+}
+
+// keys returns the caller's bucket keys. Buckets are per tenant, so one
+// studio's traffic never uses up another's budget.
+func keys(c *gin.Context, name string) []string {
+	if tenant := c.GetString(auth.TenantKey); tenant != "" {
+		name = tenant + ":" + name
+	}
+	keys := []string{name + ":ip:" + c.ClientIP()}
+	if accountID := c.GetString("account_id"); accountID != "" {
+		keys = append(keys, name+":account:"+accountID)
+	}
+	return keys
+}
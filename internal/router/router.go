@@ -1,46 +1,296 @@
 package router
 
 import (
+	"context"
 	"net/http"
+	"time"
 
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/hand/internal/blocks"
+	"github.com/bananalabs-oss/hand/internal/events"
+	"github.com/bananalabs-oss/hand/internal/openapi"
 	"github.com/bananalabs-oss/hand/internal/parties"
-	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/bananalabs-oss/hand/internal/textfilter"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/uptrace/bun"
+	"google.golang.org/grpc"
 )
 
-func Setup(db *bun.DB, jwtSecret, serviceToken string) *gin.Engine {
-	r := gin.Default()
+// DefaultRateLimits are applied per account and per IP to each named route
+// unless overridden. Every route has its own name, so polling one never
+// throttles another. "join_invalid" is charged only for join attempts with
+// an unknown invite code.
+var DefaultRateLimits = map[string]ratelimit.Limit{
+	"create":          {Burst: 10, Period: time.Minute},
+	"mine":            {Burst: 120, Period: time.Minute},
+	"join":            {Burst: 20, Period: time.Minute},
+	"join_invalid":    {Burst: 5, Period: 10 * time.Minute},
+	"leave":           {Burst: 20, Period: time.Minute},
+	"kick":            {Burst: 30, Period: time.Minute},
+	"list_bans":       {Burst: 120, Period: time.Minute},
+	"unban":           {Burst: 30, Period: time.Minute},
+	"transfer":        {Burst: 10, Period: time.Minute},
+	"promote":         {Burst: 20, Period: time.Minute},
+	"demote":          {Burst: 20, Period: time.Minute},
+	"get_settings":    {Burst: 120, Period: time.Minute},
+	"settings":        {Burst: 10, Period: time.Minute},
+	"profile":         {Burst: 10, Period: time.Minute},
+	"metadata":        {Burst: 30, Period: time.Minute},
+	"member_metadata": {Burst: 30, Period: time.Minute},
+	"disband":         {Burst: 10, Period: time.Minute},
+	"invite":          {Burst: 5, Period: time.Minute},
+	"invites":         {Burst: 20, Period: time.Minute},
+	"list_invites":    {Burst: 120, Period: time.Minute},
+	"revoke_invite":   {Burst: 20, Period: time.Minute},
+	"accept_invite":   {Burst: 20, Period: time.Minute},
+	"decline_invite":  {Burst: 20, Period: time.Minute},
+	"merge":           {Burst: 10, Period: time.Minute},
+	"list_merges":     {Burst: 120, Period: time.Minute},
+	"cancel_merge":    {Burst: 10, Period: time.Minute},
+	"accept_merge":    {Burst: 10, Period: time.Minute},
+	"decline_merge":   {Burst: 10, Period: time.Minute},
+	"split":           {Burst: 10, Period: time.Minute},
+	"groups":          {Burst: 30, Period: time.Minute},
+	"delete_group":    {Burst: 30, Period: time.Minute},
+	"join_group":      {Burst: 30, Period: time.Minute},
+	"leave_group":     {Burst: 30, Period: time.Minute},
+	"assign_group":    {Burst: 30, Period: time.Minute},
+	"chat":            {Burst: 20, Period: 10 * time.Second},
+	"chat_history":    {Burst: 60, Period: time.Minute},
+	"delete_chat":     {Burst: 20, Period: 10 * time.Second},
+	"ready":           {Burst: 30, Period: time.Minute},
+	"spectate":        {Burst: 20, Period: time.Minute},
+	"play":            {Burst: 20, Period: time.Minute},
+	"ws_connect":      {Burst: 10, Period: time.Minute},
+	"heartbeat":       {Burst: 12, Period: time.Minute},
+	"list_blocks":     {Burst: 120, Period: time.Minute},
+	"blocks":          {Burst: 30, Period: time.Minute},
+	"unblock":         {Burst: 30, Period: time.Minute},
+}
+
+type Config struct {
+	// Tenants holds each tenant's JWT secret, service token and rate limit
+	// overrides.
+	Tenants *tenants.Registry
+
+	// RateLimits overrides DefaultRateLimits by route name for every
+	// tenant.
+	RateLimits map[string]ratelimit.Limit
+	// RateLimitStore backs the limiter. Defaults to an in-memory store.
+	RateLimitStore ratelimit.Store
+
+	InviteCodes   parties.InviteCodeFormat
+	ChatRetention parties.ChatRetention
+	Gateway       parties.GatewayConfig
+	Presence      parties.PresenceConfig
+	Expiry        parties.ExpiryConfig
+
+	// BlockProvider is an optional external block graph.
+	BlockProvider blocks.Provider
+	// TextFilter screens party names and descriptions.
+	TextFilter textfilter.Filter
+	// Games holds per-namespace settings.
+	Games map[string]parties.GameConfig
+	// InviteTTL is how long a direct invite holds a player slot.
+	InviteTTL time.Duration
+
+	// GRPC, when set, gets the party service registered on it. Create it
+	// with Tenants.GRPCServerOptions.
+	GRPC *grpc.Server
+
+	// SpecViolation, when set, receives every request or response that
+	// doesn't match the OpenAPI document. Tests use it to keep the document
+	// honest; see openapi.Validator.
+	SpecViolation func(*gin.Context, error)
+}
+
+// Setup builds the HTTP router and starts Hand's background workers, which
+// run until ctx is done.
+func Setup(ctx context.Context, db *bun.DB, cfg Config) *gin.Engine {
+	// Like gin.Default, but with any token dropped from the query first so
+	// the access log never records one.
+	r := gin.New()
+	r.Use(stripQueryToken, gin.Logger(), gin.Recovery())
+	if cfg.SpecViolation != nil {
+		r.Use(openapi.Validator(cfg.SpecViolation))
+	}
+
+	limits := make(map[string]ratelimit.Limit, len(DefaultRateLimits))
+	for name, limit := range DefaultRateLimits {
+		limits[name] = limit
+	}
+	for name, limit := range cfg.RateLimits {
+		limits[name] = limit
+	}
+
+	tenantLimits := make(map[string]map[string]ratelimit.Limit)
+	for _, t := range cfg.Tenants.All() {
+		merged := make(map[string]ratelimit.Limit, len(limits))
+		for name, limit := range limits {
+			merged[name] = limit
+		}
+		for name, limit := range t.Limits() {
+			merged[name] = limit
+		}
+		tenantLimits[t.ID] = merged
+	}
+	limitFor := func(name string) func(*gin.Context) ratelimit.Limit {
+		return func(c *gin.Context) ratelimit.Limit {
+			if tl, ok := tenantLimits[c.GetString(auth.TenantKey)]; ok {
+				return tl[name]
+			}
+			return limits[name]
+		}
+	}
+
+	store := cfg.RateLimitStore
+	if store == nil {
+		store = ratelimit.NewMemoryStore()
+	}
+	limiter := ratelimit.New(store)
+	limit := func(name string) gin.HandlerFunc {
+		return limiter.MiddlewareFunc(name, limitFor(name))
+	}
 
-	h := parties.NewHandler(db)
+	h := parties.NewHandler(db, parties.Config{
+		Limiter:          limiter,
+		InvalidCodeLimit: limitFor("join_invalid"),
+		InviteCodes:      cfg.InviteCodes,
+		ChatRetention:    cfg.ChatRetention,
+		Events:           events.NewHub(),
+		Gateway:          cfg.Gateway,
+		Presence:         cfg.Presence,
+		Expiry:           cfg.Expiry,
+		Blocks:           cfg.BlockProvider,
+		TextFilter:       cfg.TextFilter,
+		Games:            cfg.Games,
+		InviteTTL:        cfg.InviteTTL,
+	})
+
+	if cfg.GRPC != nil {
+		h.RegisterGRPC(cfg.GRPC)
+	}
+
+	go h.RunPresenceReaper(ctx)
+	go h.RunExpiryScheduler(ctx)
+	go h.RunChatSweeper(ctx)
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "hand"})
 	})
+	r.GET("/openapi.json", openapi.Serve)
+
+	jwtAuth := auth.JWTAuth(cfg.Tenants)
+	// Player-facing endpoints (JWT auth, signed with the tenant's secret)
+	player := func(method, path, limitName string, handler gin.HandlerFunc) endpoint {
+		return endpoint{method, "/parties" + path, []gin.HandlerFunc{jwtAuth, h.ResolveGame, limit(limitName), handler}}
+	}
+
+	// Internal endpoints (per-tenant, scoped service credentials). The gRPC
+	// service mirrors these.
+	serviceAuth := cfg.Tenants.ServiceAuth()
+	internal := func(method, path, scope string, handler gin.HandlerFunc) endpoint {
+		return endpoint{method, "/internal/parties" + path, []gin.HandlerFunc{serviceAuth, tenants.RequireScope(scope), handler}}
+	}
+
+	v1 := version{
+		// WebSocket gateway. Browsers can't set headers on the upgrade
+		// request, so the JWT may also be passed as a subprotocol.
+		{http.MethodGet, "/parties/ws", []gin.HandlerFunc{tokenFromProtocol, jwtAuth, h.ResolveGame, limit("ws_connect"), h.Gateway}},
+
+		player(http.MethodPost, "", "create", h.CreateParty),
+		player(http.MethodGet, "/mine", "mine", h.GetMyParty),
+		player(http.MethodPost, "/join", "join", h.JoinParty),
+		player(http.MethodPost, "/leave", "leave", h.LeaveParty),
+		player(http.MethodPost, "/kick", "kick", h.KickMember),
+		player(http.MethodGet, "/bans", "list_bans", h.ListBans),
+		player(http.MethodDelete, "/bans/:accountId", "unban", h.LiftBan),
+		player(http.MethodPost, "/transfer", "transfer", h.TransferOwnership),
+		player(http.MethodPost, "/promote", "promote", h.PromoteMember),
+		player(http.MethodPost, "/demote", "demote", h.DemoteMember),
+		player(http.MethodGet, "/settings", "get_settings", h.GetSettings),
+		player(http.MethodPatch, "/settings", "settings", h.UpdateSettings),
+		player(http.MethodPatch, "/profile", "profile", h.UpdateProfile),
+		player(http.MethodPatch, "/metadata", "metadata", h.UpdatePartyMetadata),
+		player(http.MethodPatch, "/metadata/me", "member_metadata", h.UpdateMemberMetadata),
+		player(http.MethodDelete, "", "disband", h.DisbandParty),
+		player(http.MethodPost, "/invite", "invite", h.RegenerateInvite),
+		player(http.MethodPost, "/invites", "invites", h.SendInvite),
+		player(http.MethodGet, "/invites", "list_invites", h.GetInvites),
+		player(http.MethodDelete, "/invites/:accountId", "revoke_invite", h.RevokeInvite),
+		player(http.MethodPost, "/invites/:partyId/accept", "accept_invite", h.AcceptInvite),
+		player(http.MethodPost, "/invites/:partyId/decline", "decline_invite", h.DeclineInvite),
+		player(http.MethodPost, "/merge", "merge", h.ProposeMerge),
+		player(http.MethodGet, "/merge", "list_merges", h.GetMergeProposals),
+		player(http.MethodDelete, "/merge", "cancel_merge", h.CancelMerge),
+		player(http.MethodPost, "/merge/:partyId/accept", "accept_merge", h.AcceptMerge),
+		player(http.MethodPost, "/merge/:partyId/decline", "decline_merge", h.DeclineMerge),
+		player(http.MethodPost, "/split", "split", h.SplitParty),
+		player(http.MethodPost, "/groups", "groups", h.CreateGroup),
+		player(http.MethodDelete, "/groups/:groupId", "delete_group", h.DeleteGroup),
+		player(http.MethodPost, "/groups/:groupId/join", "join_group", h.JoinGroup),
+		player(http.MethodPost, "/groups/leave", "leave_group", h.LeaveGroup),
+		player(http.MethodPost, "/groups/assign", "assign_group", h.AssignGroup),
+		player(http.MethodGet, "/chat", "chat_history", h.GetMessages),
+		player(http.MethodPost, "/chat", "chat", h.PostMessage),
+		player(http.MethodDelete, "/chat/:messageId", "delete_chat", h.DeleteMessage),
+		player(http.MethodPost, "/ready", "ready", h.SetReady),
+		player(http.MethodPost, "/spectate", "spectate", h.Spectate),
+		player(http.MethodPost, "/play", "play", h.Play),
+		player(http.MethodPost, "/heartbeat", "heartbeat", h.Heartbeat),
+		player(http.MethodGet, "/blocks", "list_blocks", h.ListBlocks),
+		player(http.MethodPost, "/blocks", "blocks", h.BlockAccount),
+		player(http.MethodDelete, "/blocks/:accountId", "unblock", h.UnblockAccount),
 
-	// Player-facing endpoints (JWT auth via Potassium)
-	api := r.Group("/parties")
-	api.Use(middleware.JWTAuth(middleware.JWTConfig{
-		Secret: []byte(jwtSecret),
-	}))
-	{
-		api.POST("", h.CreateParty)
-		api.GET("/mine", h.GetMyParty)
-		api.POST("/join", h.JoinParty)
-		api.POST("/leave", h.LeaveParty)
-		api.POST("/kick", h.KickMember)
-		api.POST("/transfer", h.TransferOwnership)
-		api.DELETE("", h.DisbandParty)
-		api.POST("/invite", h.RegenerateInvite)
-	}
-
-	// Internal endpoints (service token auth via Potassium)
-	internal := r.Group("/internal/parties")
-	internal.Use(middleware.ServiceAuth(serviceToken))
-	{
-		internal.GET("/:partyId", h.GetPartyByID)
-		internal.GET("/player/:userId", h.GetPlayerParty)
+		internal(http.MethodPost, "/batch", tenants.ScopeRead, h.BatchGetParties),
+		internal(http.MethodGet, "/:partyId", tenants.ScopeRead, h.GetPartyByID),
+		internal(http.MethodPost, "/:partyId/lock", tenants.ScopeLock, h.LockParty),
+		internal(http.MethodDelete, "/:partyId/lock", tenants.ScopeLock, h.UnlockParty),
+		internal(http.MethodPost, "/:partyId/split", tenants.ScopeLock, h.SplitPartyByID),
+		internal(http.MethodPost, "/player/batch", tenants.ScopeRead, h.BatchGetPlayerParties),
+		internal(http.MethodGet, "/player/:userId", tenants.ScopeRead, h.GetPlayerParty),
+	}
+	v1.mount(r.Group("/v1"))
+	// The unversioned paths are v1 for clients built before versioning.
+	v1.mount(r.Group("", deprecated("/v1")))
+
+	// A version whose handlers differ is v1 with those swapped out, e.g.
+	//
+	//	v2 := v1.with(player(http.MethodPost, "/leave", "leave", h.LeavePartyV2))
+	//	v2.mount(r.Group("/v2"))
+
+	// Every route must be in the OpenAPI document.
+	if err := openapi.Covers(r.Routes()); err != nil {
+		panic(err)
 	}
 
 	return r
 }
+
+// tokenFromProtocol takes the JWT from the subprotocol following
+// parties.TokenSubprotocol when there is no Authorization header.
+func tokenFromProtocol(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		protocols := websocket.Subprotocols(c.Request)
+		for i, p := range protocols {
+			if p == parties.TokenSubprotocol && i+1 < len(protocols) {
+				c.Request.Header.Set("Authorization", "Bearer "+protocols[i+1])
+				break
+			}
+		}
+	}
+	c.Next()
+}
+
+// stripQueryToken removes a token query parameter, which older clients
+// still send, before anything can log the URL.
+func stripQueryToken(c *gin.Context) {
+	if q := c.Request.URL.Query(); q.Has("token") {
+		q.Del("token")
+		c.Request.URL.RawQuery = q.Encode()
+	}
+	c.Next()
+}
@@ -0,0 +1,154 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/models"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+	"github.com/bananalabs-oss/hand/internal/tenants"
+	"github.com/bananalabs-oss/potassium/database"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testSecret = "test-secret"
+
+// newTestRouter builds a router on a fresh SQLite database that fails the
+// test on any request or response the OpenAPI document doesn't allow.
+func newTestRouter(t *testing.T, limits map[string]ratelimit.Limit) http.Handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	db, err := database.Connect("sqlite://" + filepath.Join(t.TempDir(), "hand.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = database.Migrate(ctx, db, []interface{}{
+		(*models.Party)(nil),
+		(*models.PartyMember)(nil),
+		(*models.PartyMessage)(nil),
+		(*models.PartyBan)(nil),
+		(*models.Block)(nil),
+		(*models.PartyMerge)(nil),
+		(*models.PartyGroup)(nil),
+		(*models.PartyInvite)(nil),
+	}, []database.Index{
+		{Name: "idx_party_members_scope_account", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_members_scope_account ON party_members (tenant, game, account_id)"},
+		{Name: "idx_party_bans_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_bans_unique ON party_bans (party_id, account_id)"},
+		{Name: "idx_party_invites_unique", Query: "CREATE UNIQUE INDEX IF NOT EXISTS idx_party_invites_unique ON party_invites (party_id, account_id)"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := tenants.New([]tenants.Tenant{{ID: tenants.Default, JWTSecret: testSecret, ServiceToken: "test-service-token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Setup(ctx, db, Config{
+		Tenants:    registry,
+		RateLimits: limits,
+		SpecViolation: func(_ *gin.Context, err error) {
+			t.Error(err)
+		},
+	})
+}
+
+// player sends requests as a new account.
+type player struct {
+	t     *testing.T
+	r     http.Handler
+	id    uuid.UUID
+	token string
+}
+
+func newPlayer(t *testing.T, r http.Handler) *player {
+	id := uuid.New()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"account_id": id.String(),
+		"session_id": uuid.NewString(),
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &player{t: t, r: r, id: id, token: token}
+}
+
+// do sends a request and decodes the response body into out, if given.
+func (p *player) do(method, path string, body, out any) int {
+	p.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			p.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	p.r.ServeHTTP(w, req)
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			p.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return w.Code
+}
+
+func TestJoinAndKick(t *testing.T) {
+	r := newTestRouter(t, nil)
+	owner := newPlayer(t, r)
+	guest := newPlayer(t, r)
+
+	var party models.Party
+	if status := owner.do(http.MethodPost, "/parties", nil, &party); status != http.StatusCreated {
+		t.Fatalf("create: got %d", status)
+	}
+
+	var joined models.Party
+	status := guest.do(http.MethodPost, "/parties/join", gin.H{"invite_code": party.InviteCode}, &joined)
+	if status != http.StatusOK {
+		t.Fatalf("join: got %d", status)
+	}
+	if len(joined.Members) != 2 {
+		t.Fatalf("join: got %d members, want 2", len(joined.Members))
+	}
+
+	status = owner.do(http.MethodPost, "/parties/kick", gin.H{"account_id": guest.id, "ban": true}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("kick: got %d", status)
+	}
+	if status := guest.do(http.MethodGet, "/parties/mine", nil, nil); status != http.StatusNotFound {
+		t.Fatalf("mine after kick: got %d, want 404", status)
+	}
+	status = guest.do(http.MethodPost, "/parties/join", gin.H{"invite_code": party.InviteCode}, nil)
+	if status != http.StatusForbidden {
+		t.Fatalf("join after ban: got %d, want 403", status)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	r := newTestRouter(t, map[string]ratelimit.Limit{"mine": {Burst: 1, Period: time.Minute}})
+	p := newPlayer(t, r)
+
+	p.do(http.MethodGet, "/parties/mine", nil, nil)
+	if status := p.do(http.MethodGet, "/parties/mine", nil, nil); status != http.StatusTooManyRequests {
+		t.Fatalf("got %d, want 429", status)
+	}
+}
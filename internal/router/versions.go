@@ -0,0 +1,60 @@
+package router
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// endpoint is one route of an API version. Its handlers include the
+// route's own auth and rate limit middleware.
+type endpoint struct {
+	method, path string
+	handlers     []gin.HandlerFunc
+}
+
+// version is the route table of one API version.
+type version []endpoint
+
+// with returns a copy of v with changes applied: each replaces the
+// endpoint with the same method and path, or is added. A new version is
+// the previous one with the handlers whose behavior differs swapped out.
+func (v version) with(changes ...endpoint) version {
+	out := append(version(nil), v...)
+	for _, change := range changes {
+		replaced := false
+		for i, e := range out {
+			if e.method == change.method && e.path == change.path {
+				out[i] = change
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, change)
+		}
+	}
+	return out
+}
+
+func (v version) mount(g *gin.RouterGroup) {
+	for _, e := range v {
+		g.Handle(e.method, e.path, e.handlers...)
+	}
+}
+
+// unversionedDeprecatedAt is when the unversioned paths were deprecated in
+// favor of /v1.
+var unversionedDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// deprecated marks every response on an alias group with a Deprecation
+// header and links the same path under prefix as its successor.
+func deprecated(prefix string) gin.HandlerFunc {
+	since := "@" + strconv.FormatInt(unversionedDeprecatedAt.Unix(), 10)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", since)
+		c.Header("Link", "<"+prefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
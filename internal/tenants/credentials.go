@@ -0,0 +1,217 @@
+package tenants
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/potassium/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Scopes granted to service credentials. ScopeAdmin implies every other
+// scope.
+const (
+	ScopeRead  = "parties:read"
+	ScopeLock  = "parties:lock"
+	ScopeAdmin = "parties:admin"
+)
+
+// legacyCredential names the credential Tenant.ServiceToken stands for.
+const legacyCredential = "service_token"
+
+var (
+	knownScopes = map[string]bool{ScopeRead: true, ScopeLock: true, ScopeAdmin: true}
+	namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+)
+
+// Credential is a named service token. A tenant may hold several at once,
+// so a token is rotated by adding its replacement, moving callers over and
+// then removing it.
+type Credential struct {
+	Name   string   `json:"name"`
+	Token  string   `json:"token"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt stops the credential working after the given time. Nil
+	// never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// credential is a Credential bound to its tenant.
+type credential struct {
+	Credential
+	tenant *Tenant
+}
+
+func (c *credential) expired(now time.Time) bool {
+	return c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
+
+// Caller is an authenticated service: the tenant and name of the
+// credential it presented.
+type Caller struct {
+	Tenant string
+	Name   string
+	scopes []string
+}
+
+// Allows reports whether the caller's credential grants scope.
+func (c *Caller) Allows(scope string) bool {
+	for _, s := range c.scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying caller.
+func WithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the service caller authenticated for ctx, by
+// ServiceAuth or the gRPC interceptors.
+func CallerFrom(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(*Caller)
+	return caller, ok && caller != nil
+}
+
+// Authentication failures. Their messages are the ones Potassium's
+// ServiceAuth responds with.
+var (
+	ErrMissingToken = errors.New("missing service token")
+	ErrInvalidToken = errors.New("invalid service token")
+	ErrExpiredToken = errors.New("expired service token")
+)
+
+// Authenticate resolves a service token to its caller.
+func (r *Registry) Authenticate(token string) (*Caller, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	cred := r.byToken(token)
+	if cred == nil {
+		return nil, ErrInvalidToken
+	}
+	if cred.expired(time.Now()) {
+		log.Printf("Rejected expired service credential %s/%s", cred.tenant.ID, cred.Name)
+		return nil, ErrExpiredToken
+	}
+	return &Caller{Tenant: cred.tenant.ID, Name: cred.Name, scopes: cred.Scopes}, nil
+}
+
+// addCredentials validates t's credentials and registers them. tokens maps
+// every token seen so far to its owner, as no two credentials may share one.
+func (r *Registry) addCredentials(t *Tenant, tokens map[string]string) error {
+	creds := t.ServiceCredentials
+	if t.ServiceToken != "" {
+		creds = append([]Credential{{Name: legacyCredential, Token: t.ServiceToken, Scopes: []string{ScopeAdmin}}}, creds...)
+	}
+	if len(creds) == 0 {
+		return fmt.Errorf("tenant %q needs a service_token or service_credentials", t.ID)
+	}
+
+	names := make(map[string]bool, len(creds))
+	for _, cred := range creds {
+		if !namePattern.MatchString(cred.Name) || names[cred.Name] {
+			return fmt.Errorf("tenant %q: invalid or duplicate credential name %q", t.ID, cred.Name)
+		}
+		names[cred.Name] = true
+		if cred.Token == "" {
+			return fmt.Errorf("tenant %q: credential %q has no token", t.ID, cred.Name)
+		}
+		if len(cred.Scopes) == 0 {
+			return fmt.Errorf("tenant %q: credential %q has no scopes", t.ID, cred.Name)
+		}
+		for _, scope := range cred.Scopes {
+			if !knownScopes[scope] {
+				return fmt.Errorf("tenant %q: credential %q has unknown scope %q", t.ID, cred.Name, scope)
+			}
+		}
+		owner := t.ID + "/" + cred.Name
+		if other, ok := tokens[cred.Token]; ok {
+			return fmt.Errorf("credentials %s and %s share a token", other, owner)
+		}
+		tokens[cred.Token] = owner
+		r.credentials = append(r.credentials, credential{Credential: cred, tenant: t})
+	}
+	return nil
+}
+
+// byToken compares token against every credential so the time taken
+// doesn't reveal which credential, if any, it belongs to.
+func (r *Registry) byToken(token string) *credential {
+	var match *credential
+	for i := range r.credentials {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.credentials[i].Token)) == 1 {
+			match = &r.credentials[i]
+		}
+	}
+	return match
+}
+
+// ServiceAuth replaces Potassium's middleware.ServiceAuth with named,
+// per-tenant credentials, and responds the same way on failure. The
+// caller's tenant is stored under auth.TenantKey, its name under
+// auth.ServiceKey and the caller itself in the request context, and every
+// request is logged with the caller's name.
+func (r *Registry) ServiceAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, err := r.Authenticate(c.GetHeader("X-Service-Token"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Set(auth.TenantKey, caller.Tenant)
+		c.Set(auth.ServiceKey, caller.Name)
+		c.Request = c.Request.WithContext(WithCaller(c.Request.Context(), caller))
+		c.Next()
+		log.Printf("Service %s/%s: %s %s -> %d", caller.Tenant, caller.Name, c.Request.Method, c.Request.URL.Path, c.Writer.Status())
+	}
+}
+
+// RequireScope rejects service callers whose credential lacks scope. It
+// must run after ServiceAuth.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := CallerFrom(c.Request.Context())
+		if !ok || !caller.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, middleware.ErrorResponse{
+				Error:   "insufficient_scope",
+				Message: "This credential lacks the " + scope + " scope",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CredentialSummary describes the tenant's service credentials for startup
+// logs, without their tokens.
+func (t *Tenant) CredentialSummary() []string {
+	var out []string
+	if t.ServiceToken != "" {
+		out = append(out, legacyCredential+" ["+ScopeAdmin+"]")
+	}
+	for _, cred := range t.ServiceCredentials {
+		s := cred.Name + " [" + strings.Join(cred.Scopes, " ") + "]"
+		if cred.ExpiresAt != nil {
+			s += " until " + cred.ExpiresAt.Format(time.RFC3339)
+		}
+		out = append(out, s)
+	}
+	return out
+}
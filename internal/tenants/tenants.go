@@ -0,0 +1,148 @@
+// Package tenants holds the studios a Hand deployment serves. Each tenant
+// has its own token keys, service token and rate limits, and its parties,
+// memberships and blocks are invisible to every other tenant.
+package tenants
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/bananalabs-oss/hand/internal/auth"
+	"github.com/bananalabs-oss/hand/internal/ratelimit"
+)
+
+// Default is the tenant for tokens without a tenant claim, configured from
+// JWT_SECRET, JWT_JWKS and SERVICE_TOKEN.
+const Default = "default"
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant is one studio's configuration. Player tokens are verified with
+// JWTSecret (or JWTPreviousSecret while rotating) if HMAC-signed, and
+// against the JWKS document at JWKS if RS256 or EdDSA. At least one of
+// JWTSecret and JWKS is required.
+type Tenant struct {
+	ID                string `json:"id"`
+	JWTSecret         string `json:"jwt_secret,omitempty"`
+	JWTPreviousSecret string `json:"jwt_previous_secret,omitempty"`
+	// JWKS is a file path or http(s) URL.
+	JWKS     string `json:"jwks,omitempty"`
+	Issuer   string `json:"issuer,omitempty"`
+	Audience string `json:"audience,omitempty"`
+	// ServiceToken is shorthand for a credential named "service_token"
+	// with every scope.
+	ServiceToken       string       `json:"service_token,omitempty"`
+	ServiceCredentials []Credential `json:"service_credentials,omitempty"`
+	// RateLimits overrides the deployment's limits by route name, written
+	// as in RATE_LIMITS, e.g. {"join": "40/1m"}.
+	RateLimits map[string]string `json:"rate_limits,omitempty"`
+
+	limits   map[string]ratelimit.Limit
+	verifier *auth.Verifier
+}
+
+// Limits returns the parsed rate limit overrides.
+func (t *Tenant) Limits() map[string]ratelimit.Limit {
+	return t.limits
+}
+
+// Registry looks tenants up by ID and by service token.
+type Registry struct {
+	byID        map[string]*Tenant
+	list        []*Tenant
+	credentials []credential
+}
+
+// New validates tenants, loads their JWKS documents and builds a registry.
+// IDs must be unique, and every tenant needs token keys and at least one
+// service credential of its own.
+func New(tenants []Tenant) (*Registry, error) {
+	r := &Registry{byID: make(map[string]*Tenant, len(tenants))}
+	tokens := make(map[string]string, len(tenants))
+	for i := range tenants {
+		t := tenants[i]
+		if !idPattern.MatchString(t.ID) {
+			return nil, fmt.Errorf("invalid tenant id %q", t.ID)
+		}
+		if _, ok := r.byID[t.ID]; ok {
+			return nil, fmt.Errorf("duplicate tenant %q", t.ID)
+		}
+		if t.JWTSecret == "" && t.JWKS == "" {
+			return nil, fmt.Errorf("tenant %q needs a jwt_secret or jwks", t.ID)
+		}
+		if t.JWTPreviousSecret != "" && t.JWTSecret == "" {
+			return nil, fmt.Errorf("tenant %q has a jwt_previous_secret but no jwt_secret", t.ID)
+		}
+		if err := r.addCredentials(&t, tokens); err != nil {
+			return nil, err
+		}
+
+		t.limits = make(map[string]ratelimit.Limit, len(t.RateLimits))
+		for name, value := range t.RateLimits {
+			limit, err := ratelimit.ParseLimit(value)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %q: %w", t.ID, err)
+			}
+			t.limits[name] = limit
+		}
+
+		t.verifier = &auth.Verifier{Issuer: t.Issuer, Audience: t.Audience}
+		for _, secret := range []string{t.JWTSecret, t.JWTPreviousSecret} {
+			if secret != "" {
+				t.verifier.Secrets = append(t.verifier.Secrets, []byte(secret))
+			}
+		}
+		if t.JWKS != "" {
+			keySet, err := auth.NewKeySet(t.JWKS)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %q: %w", t.ID, err)
+			}
+			t.verifier.JWKS = keySet
+		}
+
+		r.byID[t.ID] = &t
+		r.list = append(r.list, &t)
+	}
+	if len(r.list) == 0 {
+		return nil, fmt.Errorf("no tenants configured")
+	}
+	return r, nil
+}
+
+// Load reads a JSON array of tenants from path.
+func Load(path string) ([]Tenant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return tenants, nil
+}
+
+// Get returns the tenant with the given ID. An empty ID is Default.
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	if id == "" {
+		id = Default
+	}
+	t, ok := r.byID[id]
+	return t, ok
+}
+
+// All returns every tenant in configuration order.
+func (r *Registry) All() []*Tenant {
+	return r.list
+}
+
+// Verifier implements auth.Keys.
+func (r *Registry) Verifier(tenant string) (string, *auth.Verifier, bool) {
+	t, ok := r.Get(tenant)
+	if !ok {
+		return "", nil, false
+	}
+	return t.ID, t.verifier, true
+}
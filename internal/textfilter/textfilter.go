@@ -0,0 +1,69 @@
+// Package textfilter screens player-written text such as party names for
+// words a deployment doesn't allow. Filters are pluggable so a hosted
+// moderation service can replace the local word list.
+package textfilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Filter reports the first disallowed word in text, if any.
+type Filter interface {
+	Match(text string) (word string, found bool)
+}
+
+// WordList rejects text containing any listed word. Matching is by whole
+// word and ignores case, so a listed word inside a longer one is allowed.
+type WordList struct {
+	words map[string]struct{}
+}
+
+func NewWordList(words []string) *WordList {
+	l := &WordList{words: make(map[string]struct{}, len(words))}
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			l.words[w] = struct{}{}
+		}
+	}
+	return l
+}
+
+// LoadWordList reads a word list with one word per line. Blank lines and
+// lines starting with # are skipped.
+func LoadWordList(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read word list %s: %w", path, err)
+	}
+	return NewWordList(words), nil
+}
+
+func (l *WordList) Match(text string) (string, bool) {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, f := range fields {
+		if _, ok := l.words[f]; ok {
+			return f, true
+		}
+	}
+	return "", false
+}
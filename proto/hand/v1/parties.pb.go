@@ -0,0 +1,1175 @@
+// Hand's service-to-service API. It mirrors the /internal/parties HTTP
+// routes and uses the same service credentials, sent as the
+// "x-service-token" metadata key.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: hand/v1/parties.proto
+
+package handv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Party struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OwnerId     string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Game        string                 `protobuf:"bytes,3,opt,name=game,proto3" json:"game,omitempty"`
+	MaxSize     int32                  `protobuf:"varint,4,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	Name        string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Emblem      string                 `protobuf:"bytes,7,opt,name=emblem,proto3" json:"emblem,omitempty"`
+	Metadata    *structpb.Struct       `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Members     []*Member              `protobuf:"bytes,9,rep,name=members,proto3" json:"members,omitempty"`
+	// Set while the party is locked.
+	Lock      *Lock                  `protobuf:"bytes,10,opt,name=lock,proto3" json:"lock,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Set once the party has been merged or split and has no members.
+	ArchivedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	// The party this one was merged into, if any.
+	MergedInto string `protobuf:"bytes,14,opt,name=merged_into,json=mergedInto,proto3" json:"merged_into,omitempty"`
+	// The party this one was split from, if any.
+	SplitFrom string   `protobuf:"bytes,15,opt,name=split_from,json=splitFrom,proto3" json:"split_from,omitempty"`
+	Groups    []*Group `protobuf:"bytes,16,rep,name=groups,proto3" json:"groups,omitempty"`
+	// Seats for spectators on top of max_size.
+	SpectatorSlots int32 `protobuf:"varint,17,opt,name=spectator_slots,json=spectatorSlots,proto3" json:"spectator_slots,omitempty"`
+	// The code players join with.
+	InviteCode    string `protobuf:"bytes,18,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Party) Reset() {
+	*x = Party{}
+	mi := &file_hand_v1_parties_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Party) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Party) ProtoMessage() {}
+
+func (x *Party) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Party.ProtoReflect.Descriptor instead.
+func (*Party) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Party) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Party) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Party) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *Party) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *Party) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Party) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Party) GetEmblem() string {
+	if x != nil {
+		return x.Emblem
+	}
+	return ""
+}
+
+func (x *Party) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Party) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *Party) GetLock() *Lock {
+	if x != nil {
+		return x.Lock
+	}
+	return nil
+}
+
+func (x *Party) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Party) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Party) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *Party) GetMergedInto() string {
+	if x != nil {
+		return x.MergedInto
+	}
+	return ""
+}
+
+func (x *Party) GetSplitFrom() string {
+	if x != nil {
+		return x.SplitFrom
+	}
+	return ""
+}
+
+func (x *Party) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *Party) GetSpectatorSlots() int32 {
+	if x != nil {
+		return x.SpectatorSlots
+	}
+	return 0
+}
+
+func (x *Party) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
+type Member struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Role      string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Ready     bool                   `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
+	Presence  string                 `protobuf:"bytes,4,opt,name=presence,proto3" json:"presence,omitempty"`
+	Metadata  *structpb.Struct       `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Unset if the member hasn't been seen since presence was tracked.
+	LastSeenAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	JoinedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	// Empty when the member isn't in a sub-group.
+	GroupId string `protobuf:"bytes,8,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// "player" or "spectator".
+	Type          string `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_hand_v1_parties_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Member) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Member) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Member) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *Member) GetPresence() string {
+	if x != nil {
+		return x.Presence
+	}
+	return ""
+}
+
+func (x *Member) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Member) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *Member) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
+func (x *Member) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *Member) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MaxSize       int32                  `protobuf:"varint,3,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_hand_v1_parties_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Group) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Group) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *Group) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Lock struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the service credential holding the lock.
+	LockedBy      string                 `protobuf:"bytes,1,opt,name=locked_by,json=lockedBy,proto3" json:"locked_by,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lock) Reset() {
+	*x = Lock{}
+	mi := &file_hand_v1_parties_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lock) ProtoMessage() {}
+
+func (x *Lock) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lock.ProtoReflect.Descriptor instead.
+func (*Lock) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Lock) GetLockedBy() string {
+	if x != nil {
+		return x.LockedBy
+	}
+	return ""
+}
+
+func (x *Lock) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type GetPartyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	PartyId string                 `protobuf:"bytes,1,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	// When set, the party must belong to this game.
+	Game          string `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPartyRequest) Reset() {
+	*x = GetPartyRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPartyRequest) ProtoMessage() {}
+
+func (x *GetPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPartyRequest.ProtoReflect.Descriptor instead.
+func (*GetPartyRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPartyRequest) GetPartyId() string {
+	if x != nil {
+		return x.PartyId
+	}
+	return ""
+}
+
+func (x *GetPartyRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+type GetPlayerPartyRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Defaults to "default".
+	Game          string `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlayerPartyRequest) Reset() {
+	*x = GetPlayerPartyRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlayerPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlayerPartyRequest) ProtoMessage() {}
+
+func (x *GetPlayerPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlayerPartyRequest.ProtoReflect.Descriptor instead.
+func (*GetPlayerPartyRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPlayerPartyRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetPlayerPartyRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+type BatchGetPartiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartyIds      []string               `protobuf:"bytes,1,rep,name=party_ids,json=partyIds,proto3" json:"party_ids,omitempty"`
+	Game          string                 `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPartiesRequest) Reset() {
+	*x = BatchGetPartiesRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPartiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPartiesRequest) ProtoMessage() {}
+
+func (x *BatchGetPartiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPartiesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetPartiesRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchGetPartiesRequest) GetPartyIds() []string {
+	if x != nil {
+		return x.PartyIds
+	}
+	return nil
+}
+
+func (x *BatchGetPartiesRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+type BatchGetPartiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keyed by party ID.
+	Parties       map[string]*Party `protobuf:"bytes,1,rep,name=parties,proto3" json:"parties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPartiesResponse) Reset() {
+	*x = BatchGetPartiesResponse{}
+	mi := &file_hand_v1_parties_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPartiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPartiesResponse) ProtoMessage() {}
+
+func (x *BatchGetPartiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPartiesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetPartiesResponse) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchGetPartiesResponse) GetParties() map[string]*Party {
+	if x != nil {
+		return x.Parties
+	}
+	return nil
+}
+
+type BatchGetPlayerPartiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountIds    []string               `protobuf:"bytes,1,rep,name=account_ids,json=accountIds,proto3" json:"account_ids,omitempty"`
+	Game          string                 `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPlayerPartiesRequest) Reset() {
+	*x = BatchGetPlayerPartiesRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPlayerPartiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPlayerPartiesRequest) ProtoMessage() {}
+
+func (x *BatchGetPlayerPartiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPlayerPartiesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetPlayerPartiesRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchGetPlayerPartiesRequest) GetAccountIds() []string {
+	if x != nil {
+		return x.AccountIds
+	}
+	return nil
+}
+
+func (x *BatchGetPlayerPartiesRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+type BatchGetPlayerPartiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keyed by account ID.
+	Parties       map[string]*Party `protobuf:"bytes,1,rep,name=parties,proto3" json:"parties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetPlayerPartiesResponse) Reset() {
+	*x = BatchGetPlayerPartiesResponse{}
+	mi := &file_hand_v1_parties_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetPlayerPartiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPlayerPartiesResponse) ProtoMessage() {}
+
+func (x *BatchGetPlayerPartiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPlayerPartiesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetPlayerPartiesResponse) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchGetPlayerPartiesResponse) GetParties() map[string]*Party {
+	if x != nil {
+		return x.Parties
+	}
+	return nil
+}
+
+type LockPartyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	PartyId string                 `protobuf:"bytes,1,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	// Up to 24 hours. Unset or zero locks for 10 minutes.
+	Ttl           *durationpb.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockPartyRequest) Reset() {
+	*x = LockPartyRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockPartyRequest) ProtoMessage() {}
+
+func (x *LockPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockPartyRequest.ProtoReflect.Descriptor instead.
+func (*LockPartyRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LockPartyRequest) GetPartyId() string {
+	if x != nil {
+		return x.PartyId
+	}
+	return ""
+}
+
+func (x *LockPartyRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+type UnlockPartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartyId       string                 `protobuf:"bytes,1,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockPartyRequest) Reset() {
+	*x = UnlockPartyRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockPartyRequest) ProtoMessage() {}
+
+func (x *UnlockPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockPartyRequest.ProtoReflect.Descriptor instead.
+func (*UnlockPartyRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnlockPartyRequest) GetPartyId() string {
+	if x != nil {
+		return x.PartyId
+	}
+	return ""
+}
+
+type WatchPartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartyId       string                 `protobuf:"bytes,1,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPartyRequest) Reset() {
+	*x = WatchPartyRequest{}
+	mi := &file_hand_v1_parties_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPartyRequest) ProtoMessage() {}
+
+func (x *WatchPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPartyRequest.ProtoReflect.Descriptor instead.
+func (*WatchPartyRequest) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchPartyRequest) GetPartyId() string {
+	if x != nil {
+		return x.PartyId
+	}
+	return ""
+}
+
+type PartyEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of the event types listed in the README, e.g. "member.joined".
+	Type      string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	PartyId   string `protobuf:"bytes,2,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	AccountId string `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ActorId   string `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// The event's data object as JSON, empty when the event has none.
+	DataJson      string                 `protobuf:"bytes,5,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartyEvent) Reset() {
+	*x = PartyEvent{}
+	mi := &file_hand_v1_parties_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyEvent) ProtoMessage() {}
+
+func (x *PartyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hand_v1_parties_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyEvent.ProtoReflect.Descriptor instead.
+func (*PartyEvent) Descriptor() ([]byte, []int) {
+	return file_hand_v1_parties_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PartyEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PartyEvent) GetPartyId() string {
+	if x != nil {
+		return x.PartyId
+	}
+	return ""
+}
+
+func (x *PartyEvent) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PartyEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *PartyEvent) GetDataJson() string {
+	if x != nil {
+		return x.DataJson
+	}
+	return ""
+}
+
+func (x *PartyEvent) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+var File_hand_v1_parties_proto protoreflect.FileDescriptor
+
+const file_hand_v1_parties_proto_rawDesc = "" +
+	"\n" +
+	"\x15hand/v1/parties.proto\x12\ahand.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x05\n" +
+	"\x05Party\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x12\n" +
+	"\x04game\x18\x03 \x01(\tR\x04game\x12\x19\n" +
+	"\bmax_size\x18\x04 \x01(\x05R\amaxSize\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06emblem\x18\a \x01(\tR\x06emblem\x123\n" +
+	"\bmetadata\x18\b \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12)\n" +
+	"\amembers\x18\t \x03(\v2\x0f.hand.v1.MemberR\amembers\x12!\n" +
+	"\x04lock\x18\n" +
+	" \x01(\v2\r.hand.v1.LockR\x04lock\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\varchived_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x1f\n" +
+	"\vmerged_into\x18\x0e \x01(\tR\n" +
+	"mergedInto\x12\x1d\n" +
+	"\n" +
+	"split_from\x18\x0f \x01(\tR\tsplitFrom\x12&\n" +
+	"\x06groups\x18\x10 \x03(\v2\x0e.hand.v1.GroupR\x06groups\x12'\n" +
+	"\x0fspectator_slots\x18\x11 \x01(\x05R\x0espectatorSlots\x12\x1f\n" +
+	"\vinvite_code\x18\x12 \x01(\tR\n" +
+	"inviteCode\"\xc8\x02\n" +
+	"\x06Member\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x14\n" +
+	"\x05ready\x18\x03 \x01(\bR\x05ready\x12\x1a\n" +
+	"\bpresence\x18\x04 \x01(\tR\bpresence\x123\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12<\n" +
+	"\flast_seen_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastSeenAt\x127\n" +
+	"\tjoined_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\x12\x19\n" +
+	"\bgroup_id\x18\b \x01(\tR\agroupId\x12\x12\n" +
+	"\x04type\x18\t \x01(\tR\x04type\"\x81\x01\n" +
+	"\x05Group\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bmax_size\x18\x03 \x01(\x05R\amaxSize\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"^\n" +
+	"\x04Lock\x12\x1b\n" +
+	"\tlocked_by\x18\x01 \x01(\tR\blockedBy\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"@\n" +
+	"\x0fGetPartyRequest\x12\x19\n" +
+	"\bparty_id\x18\x01 \x01(\tR\apartyId\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\"J\n" +
+	"\x15GetPlayerPartyRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\"I\n" +
+	"\x16BatchGetPartiesRequest\x12\x1b\n" +
+	"\tparty_ids\x18\x01 \x03(\tR\bpartyIds\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\"\xae\x01\n" +
+	"\x17BatchGetPartiesResponse\x12G\n" +
+	"\aparties\x18\x01 \x03(\v2-.hand.v1.BatchGetPartiesResponse.PartiesEntryR\aparties\x1aJ\n" +
+	"\fPartiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\v2\x0e.hand.v1.PartyR\x05value:\x028\x01\"S\n" +
+	"\x1cBatchGetPlayerPartiesRequest\x12\x1f\n" +
+	"\vaccount_ids\x18\x01 \x03(\tR\n" +
+	"accountIds\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\"\xba\x01\n" +
+	"\x1dBatchGetPlayerPartiesResponse\x12M\n" +
+	"\aparties\x18\x01 \x03(\v23.hand.v1.BatchGetPlayerPartiesResponse.PartiesEntryR\aparties\x1aJ\n" +
+	"\fPartiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\v2\x0e.hand.v1.PartyR\x05value:\x028\x01\"Z\n" +
+	"\x10LockPartyRequest\x12\x19\n" +
+	"\bparty_id\x18\x01 \x01(\tR\apartyId\x12+\n" +
+	"\x03ttl\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\"/\n" +
+	"\x12UnlockPartyRequest\x12\x19\n" +
+	"\bparty_id\x18\x01 \x01(\tR\apartyId\".\n" +
+	"\x11WatchPartyRequest\x12\x19\n" +
+	"\bparty_id\x18\x01 \x01(\tR\apartyId\"\xbe\x01\n" +
+	"\n" +
+	"PartyEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x19\n" +
+	"\bparty_id\x18\x02 \x01(\tR\apartyId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x1b\n" +
+	"\tdata_json\x18\x05 \x01(\tR\bdataJson\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at2\xf9\x03\n" +
+	"\fPartyService\x124\n" +
+	"\bGetParty\x12\x18.hand.v1.GetPartyRequest\x1a\x0e.hand.v1.Party\x12@\n" +
+	"\x0eGetPlayerParty\x12\x1e.hand.v1.GetPlayerPartyRequest\x1a\x0e.hand.v1.Party\x12T\n" +
+	"\x0fBatchGetParties\x12\x1f.hand.v1.BatchGetPartiesRequest\x1a .hand.v1.BatchGetPartiesResponse\x12f\n" +
+	"\x15BatchGetPlayerParties\x12%.hand.v1.BatchGetPlayerPartiesRequest\x1a&.hand.v1.BatchGetPlayerPartiesResponse\x126\n" +
+	"\tLockParty\x12\x19.hand.v1.LockPartyRequest\x1a\x0e.hand.v1.Party\x12:\n" +
+	"\vUnlockParty\x12\x1b.hand.v1.UnlockPartyRequest\x1a\x0e.hand.v1.Party\x12?\n" +
+	"\n" +
+	"WatchParty\x12\x1a.hand.v1.WatchPartyRequest\x1a\x13.hand.v1.PartyEvent0\x01B5Z3github.com/bananalabs-oss/hand/proto/hand/v1;handv1b\x06proto3"
+
+var (
+	file_hand_v1_parties_proto_rawDescOnce sync.Once
+	file_hand_v1_parties_proto_rawDescData []byte
+)
+
+func file_hand_v1_parties_proto_rawDescGZIP() []byte {
+	file_hand_v1_parties_proto_rawDescOnce.Do(func() {
+		file_hand_v1_parties_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hand_v1_parties_proto_rawDesc), len(file_hand_v1_parties_proto_rawDesc)))
+	})
+	return file_hand_v1_parties_proto_rawDescData
+}
+
+var file_hand_v1_parties_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_hand_v1_parties_proto_goTypes = []any{
+	(*Party)(nil),                         // 0: hand.v1.Party
+	(*Member)(nil),                        // 1: hand.v1.Member
+	(*Group)(nil),                         // 2: hand.v1.Group
+	(*Lock)(nil),                          // 3: hand.v1.Lock
+	(*GetPartyRequest)(nil),               // 4: hand.v1.GetPartyRequest
+	(*GetPlayerPartyRequest)(nil),         // 5: hand.v1.GetPlayerPartyRequest
+	(*BatchGetPartiesRequest)(nil),        // 6: hand.v1.BatchGetPartiesRequest
+	(*BatchGetPartiesResponse)(nil),       // 7: hand.v1.BatchGetPartiesResponse
+	(*BatchGetPlayerPartiesRequest)(nil),  // 8: hand.v1.BatchGetPlayerPartiesRequest
+	(*BatchGetPlayerPartiesResponse)(nil), // 9: hand.v1.BatchGetPlayerPartiesResponse
+	(*LockPartyRequest)(nil),              // 10: hand.v1.LockPartyRequest
+	(*UnlockPartyRequest)(nil),            // 11: hand.v1.UnlockPartyRequest
+	(*WatchPartyRequest)(nil),             // 12: hand.v1.WatchPartyRequest
+	(*PartyEvent)(nil),                    // 13: hand.v1.PartyEvent
+	nil,                                   // 14: hand.v1.BatchGetPartiesResponse.PartiesEntry
+	nil,                                   // 15: hand.v1.BatchGetPlayerPartiesResponse.PartiesEntry
+	(*structpb.Struct)(nil),               // 16: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),         // 17: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),           // 18: google.protobuf.Duration
+}
+var file_hand_v1_parties_proto_depIdxs = []int32{
+	16, // 0: hand.v1.Party.metadata:type_name -> google.protobuf.Struct
+	1,  // 1: hand.v1.Party.members:type_name -> hand.v1.Member
+	3,  // 2: hand.v1.Party.lock:type_name -> hand.v1.Lock
+	17, // 3: hand.v1.Party.created_at:type_name -> google.protobuf.Timestamp
+	17, // 4: hand.v1.Party.updated_at:type_name -> google.protobuf.Timestamp
+	17, // 5: hand.v1.Party.archived_at:type_name -> google.protobuf.Timestamp
+	2,  // 6: hand.v1.Party.groups:type_name -> hand.v1.Group
+	16, // 7: hand.v1.Member.metadata:type_name -> google.protobuf.Struct
+	17, // 8: hand.v1.Member.last_seen_at:type_name -> google.protobuf.Timestamp
+	17, // 9: hand.v1.Member.joined_at:type_name -> google.protobuf.Timestamp
+	17, // 10: hand.v1.Group.created_at:type_name -> google.protobuf.Timestamp
+	17, // 11: hand.v1.Lock.expires_at:type_name -> google.protobuf.Timestamp
+	14, // 12: hand.v1.BatchGetPartiesResponse.parties:type_name -> hand.v1.BatchGetPartiesResponse.PartiesEntry
+	15, // 13: hand.v1.BatchGetPlayerPartiesResponse.parties:type_name -> hand.v1.BatchGetPlayerPartiesResponse.PartiesEntry
+	18, // 14: hand.v1.LockPartyRequest.ttl:type_name -> google.protobuf.Duration
+	17, // 15: hand.v1.PartyEvent.at:type_name -> google.protobuf.Timestamp
+	0,  // 16: hand.v1.BatchGetPartiesResponse.PartiesEntry.value:type_name -> hand.v1.Party
+	0,  // 17: hand.v1.BatchGetPlayerPartiesResponse.PartiesEntry.value:type_name -> hand.v1.Party
+	4,  // 18: hand.v1.PartyService.GetParty:input_type -> hand.v1.GetPartyRequest
+	5,  // 19: hand.v1.PartyService.GetPlayerParty:input_type -> hand.v1.GetPlayerPartyRequest
+	6,  // 20: hand.v1.PartyService.BatchGetParties:input_type -> hand.v1.BatchGetPartiesRequest
+	8,  // 21: hand.v1.PartyService.BatchGetPlayerParties:input_type -> hand.v1.BatchGetPlayerPartiesRequest
+	10, // 22: hand.v1.PartyService.LockParty:input_type -> hand.v1.LockPartyRequest
+	11, // 23: hand.v1.PartyService.UnlockParty:input_type -> hand.v1.UnlockPartyRequest
+	12, // 24: hand.v1.PartyService.WatchParty:input_type -> hand.v1.WatchPartyRequest
+	0,  // 25: hand.v1.PartyService.GetParty:output_type -> hand.v1.Party
+	0,  // 26: hand.v1.PartyService.GetPlayerParty:output_type -> hand.v1.Party
+	7,  // 27: hand.v1.PartyService.BatchGetParties:output_type -> hand.v1.BatchGetPartiesResponse
+	9,  // 28: hand.v1.PartyService.BatchGetPlayerParties:output_type -> hand.v1.BatchGetPlayerPartiesResponse
+	0,  // 29: hand.v1.PartyService.LockParty:output_type -> hand.v1.Party
+	0,  // 30: hand.v1.PartyService.UnlockParty:output_type -> hand.v1.Party
+	13, // 31: hand.v1.PartyService.WatchParty:output_type -> hand.v1.PartyEvent
+	25, // [25:32] is the sub-list for method output_type
+	18, // [18:25] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_hand_v1_parties_proto_init() }
+func file_hand_v1_parties_proto_init() {
+	if File_hand_v1_parties_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hand_v1_parties_proto_rawDesc), len(file_hand_v1_parties_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hand_v1_parties_proto_goTypes,
+		DependencyIndexes: file_hand_v1_parties_proto_depIdxs,
+		MessageInfos:      file_hand_v1_parties_proto_msgTypes,
+	}.Build()
+	File_hand_v1_parties_proto = out.File
+	file_hand_v1_parties_proto_goTypes = nil
+	file_hand_v1_parties_proto_depIdxs = nil
+}
@@ -0,0 +1,385 @@
+// Hand's service-to-service API. It mirrors the /internal/parties HTTP
+// routes and uses the same service credentials, sent as the
+// "x-service-token" metadata key.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: hand/v1/parties.proto
+
+package handv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PartyService_GetParty_FullMethodName              = "/hand.v1.PartyService/GetParty"
+	PartyService_GetPlayerParty_FullMethodName        = "/hand.v1.PartyService/GetPlayerParty"
+	PartyService_BatchGetParties_FullMethodName       = "/hand.v1.PartyService/BatchGetParties"
+	PartyService_BatchGetPlayerParties_FullMethodName = "/hand.v1.PartyService/BatchGetPlayerParties"
+	PartyService_LockParty_FullMethodName             = "/hand.v1.PartyService/LockParty"
+	PartyService_UnlockParty_FullMethodName           = "/hand.v1.PartyService/UnlockParty"
+	PartyService_WatchParty_FullMethodName            = "/hand.v1.PartyService/WatchParty"
+)
+
+// PartyServiceClient is the client API for PartyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PartyServiceClient interface {
+	// GetParty returns a party with its members. Requires parties:read.
+	GetParty(ctx context.Context, in *GetPartyRequest, opts ...grpc.CallOption) (*Party, error)
+	// GetPlayerParty returns a player's party in a game. Requires
+	// parties:read.
+	GetPlayerParty(ctx context.Context, in *GetPlayerPartyRequest, opts ...grpc.CallOption) (*Party, error)
+	// BatchGetParties looks up to 100 parties at once. Unknown IDs are
+	// left out of the response. Requires parties:read.
+	BatchGetParties(ctx context.Context, in *BatchGetPartiesRequest, opts ...grpc.CallOption) (*BatchGetPartiesResponse, error)
+	// BatchGetPlayerParties looks up the parties of up to 100 players at
+	// once. Players not in a party are left out. Requires parties:read.
+	BatchGetPlayerParties(ctx context.Context, in *BatchGetPlayerPartiesRequest, opts ...grpc.CallOption) (*BatchGetPlayerPartiesResponse, error)
+	// LockParty freezes a party's roster, e.g. while it is matched. Locking
+	// again with the same credential extends the lock. Requires
+	// parties:lock.
+	LockParty(ctx context.Context, in *LockPartyRequest, opts ...grpc.CallOption) (*Party, error)
+	// UnlockParty releases a lock held by the caller, or any lock with
+	// parties:admin. Requires parties:lock.
+	UnlockParty(ctx context.Context, in *UnlockPartyRequest, opts ...grpc.CallOption) (*Party, error)
+	// WatchParty streams a party's events until it is disbanded, merged or
+	// split, or the caller disconnects. Requires parties:read.
+	WatchParty(ctx context.Context, in *WatchPartyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartyEvent], error)
+}
+
+type partyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPartyServiceClient(cc grpc.ClientConnInterface) PartyServiceClient {
+	return &partyServiceClient{cc}
+}
+
+func (c *partyServiceClient) GetParty(ctx context.Context, in *GetPartyRequest, opts ...grpc.CallOption) (*Party, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Party)
+	err := c.cc.Invoke(ctx, PartyService_GetParty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) GetPlayerParty(ctx context.Context, in *GetPlayerPartyRequest, opts ...grpc.CallOption) (*Party, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Party)
+	err := c.cc.Invoke(ctx, PartyService_GetPlayerParty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) BatchGetParties(ctx context.Context, in *BatchGetPartiesRequest, opts ...grpc.CallOption) (*BatchGetPartiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetPartiesResponse)
+	err := c.cc.Invoke(ctx, PartyService_BatchGetParties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) BatchGetPlayerParties(ctx context.Context, in *BatchGetPlayerPartiesRequest, opts ...grpc.CallOption) (*BatchGetPlayerPartiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetPlayerPartiesResponse)
+	err := c.cc.Invoke(ctx, PartyService_BatchGetPlayerParties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) LockParty(ctx context.Context, in *LockPartyRequest, opts ...grpc.CallOption) (*Party, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Party)
+	err := c.cc.Invoke(ctx, PartyService_LockParty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) UnlockParty(ctx context.Context, in *UnlockPartyRequest, opts ...grpc.CallOption) (*Party, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Party)
+	err := c.cc.Invoke(ctx, PartyService_UnlockParty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *partyServiceClient) WatchParty(ctx context.Context, in *WatchPartyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PartyService_ServiceDesc.Streams[0], PartyService_WatchParty_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchPartyRequest, PartyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PartyService_WatchPartyClient = grpc.ServerStreamingClient[PartyEvent]
+
+// PartyServiceServer is the server API for PartyService service.
+// All implementations must embed UnimplementedPartyServiceServer
+// for forward compatibility.
+type PartyServiceServer interface {
+	// GetParty returns a party with its members. Requires parties:read.
+	GetParty(context.Context, *GetPartyRequest) (*Party, error)
+	// GetPlayerParty returns a player's party in a game. Requires
+	// parties:read.
+	GetPlayerParty(context.Context, *GetPlayerPartyRequest) (*Party, error)
+	// BatchGetParties looks up to 100 parties at once. Unknown IDs are
+	// left out of the response. Requires parties:read.
+	BatchGetParties(context.Context, *BatchGetPartiesRequest) (*BatchGetPartiesResponse, error)
+	// BatchGetPlayerParties looks up the parties of up to 100 players at
+	// once. Players not in a party are left out. Requires parties:read.
+	BatchGetPlayerParties(context.Context, *BatchGetPlayerPartiesRequest) (*BatchGetPlayerPartiesResponse, error)
+	// LockParty freezes a party's roster, e.g. while it is matched. Locking
+	// again with the same credential extends the lock. Requires
+	// parties:lock.
+	LockParty(context.Context, *LockPartyRequest) (*Party, error)
+	// UnlockParty releases a lock held by the caller, or any lock with
+	// parties:admin. Requires parties:lock.
+	UnlockParty(context.Context, *UnlockPartyRequest) (*Party, error)
+	// WatchParty streams a party's events until it is disbanded, merged or
+	// split, or the caller disconnects. Requires parties:read.
+	WatchParty(*WatchPartyRequest, grpc.ServerStreamingServer[PartyEvent]) error
+	mustEmbedUnimplementedPartyServiceServer()
+}
+
+// UnimplementedPartyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPartyServiceServer struct{}
+
+func (UnimplementedPartyServiceServer) GetParty(context.Context, *GetPartyRequest) (*Party, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetParty not implemented")
+}
+func (UnimplementedPartyServiceServer) GetPlayerParty(context.Context, *GetPlayerPartyRequest) (*Party, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPlayerParty not implemented")
+}
+func (UnimplementedPartyServiceServer) BatchGetParties(context.Context, *BatchGetPartiesRequest) (*BatchGetPartiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetParties not implemented")
+}
+func (UnimplementedPartyServiceServer) BatchGetPlayerParties(context.Context, *BatchGetPlayerPartiesRequest) (*BatchGetPlayerPartiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetPlayerParties not implemented")
+}
+func (UnimplementedPartyServiceServer) LockParty(context.Context, *LockPartyRequest) (*Party, error) {
+	return nil, status.Error(codes.Unimplemented, "method LockParty not implemented")
+}
+func (UnimplementedPartyServiceServer) UnlockParty(context.Context, *UnlockPartyRequest) (*Party, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnlockParty not implemented")
+}
+func (UnimplementedPartyServiceServer) WatchParty(*WatchPartyRequest, grpc.ServerStreamingServer[PartyEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchParty not implemented")
+}
+func (UnimplementedPartyServiceServer) mustEmbedUnimplementedPartyServiceServer() {}
+func (UnimplementedPartyServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafePartyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PartyServiceServer will
+// result in compilation errors.
+type UnsafePartyServiceServer interface {
+	mustEmbedUnimplementedPartyServiceServer()
+}
+
+func RegisterPartyServiceServer(s grpc.ServiceRegistrar, srv PartyServiceServer) {
+	// If the following call panics, it indicates UnimplementedPartyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PartyService_ServiceDesc, srv)
+}
+
+func _PartyService_GetParty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPartyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).GetParty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_GetParty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).GetParty(ctx, req.(*GetPartyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_GetPlayerParty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerPartyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).GetPlayerParty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_GetPlayerParty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).GetPlayerParty(ctx, req.(*GetPlayerPartyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_BatchGetParties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPartiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).BatchGetParties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_BatchGetParties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).BatchGetParties(ctx, req.(*BatchGetPartiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_BatchGetPlayerParties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPlayerPartiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).BatchGetPlayerParties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_BatchGetPlayerParties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).BatchGetPlayerParties(ctx, req.(*BatchGetPlayerPartiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_LockParty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockPartyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).LockParty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_LockParty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).LockParty(ctx, req.(*LockPartyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_UnlockParty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockPartyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PartyServiceServer).UnlockParty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PartyService_UnlockParty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PartyServiceServer).UnlockParty(ctx, req.(*UnlockPartyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PartyService_WatchParty_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPartyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PartyServiceServer).WatchParty(m, &grpc.GenericServerStream[WatchPartyRequest, PartyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PartyService_WatchPartyServer = grpc.ServerStreamingServer[PartyEvent]
+
+// PartyService_ServiceDesc is the grpc.ServiceDesc for PartyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PartyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hand.v1.PartyService",
+	HandlerType: (*PartyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetParty",
+			Handler:    _PartyService_GetParty_Handler,
+		},
+		{
+			MethodName: "GetPlayerParty",
+			Handler:    _PartyService_GetPlayerParty_Handler,
+		},
+		{
+			MethodName: "BatchGetParties",
+			Handler:    _PartyService_BatchGetParties_Handler,
+		},
+		{
+			MethodName: "BatchGetPlayerParties",
+			Handler:    _PartyService_BatchGetPlayerParties_Handler,
+		},
+		{
+			MethodName: "LockParty",
+			Handler:    _PartyService_LockParty_Handler,
+		},
+		{
+			MethodName: "UnlockParty",
+			Handler:    _PartyService_UnlockParty_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchParty",
+			Handler:       _PartyService_WatchParty_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hand/v1/parties.proto",
+}